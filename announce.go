@@ -0,0 +1,125 @@
+// announce.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// max length of the text in one embed field, keeps the whole embed under discord's size limit
+const maxFieldLength = 700
+
+// func to post raid, egg and research changes to every configured channel
+func postChanges(s *discordgo.Session, st Store, diff *DatasetDiff) {
+	embed := buildChangeEmbed(diff)
+	if embed == nil {
+		return
+	}
+
+	// runs after a refresh, not for a command, so there is no command timeout to pass on
+	channels, err := st.AnnounceChannels(context.Background())
+	if err != nil {
+		slog.Error("Error loading announcement channels", "err", err)
+		return
+	}
+	for _, channelID := range channels {
+		_, err = s.ChannelMessageSendEmbed(channelID, embed)
+		if err != nil {
+			slog.Error("Error posting changes", "channel", channelID, "err", err)
+		}
+	}
+}
+
+// func to build the announcement embed, nil if no raids, eggs or research changed
+func buildChangeEmbed(diff *DatasetDiff) *discordgo.MessageEmbed {
+	var fields []*discordgo.MessageEmbedField
+	addField := func(name string, lines []string) {
+		if len(lines) > 0 {
+			fields = append(fields, &discordgo.MessageEmbedField{Name: name, Value: joinLines(lines, maxFieldLength)})
+		}
+	}
+
+	var added, removed []string
+	for _, r := range diff.AddedRaids {
+		added = append(added, shinyMark(r.CanBeShiny)+"**"+r.Name+"** ("+r.Tier+")")
+	}
+	for _, r := range diff.RemovedRaids {
+		removed = append(removed, "**"+r.Name+"** ("+r.Tier+")")
+	}
+	addField("New raid bosses", added)
+	addField("Left raids", removed)
+
+	added, removed = nil, nil
+	for _, e := range diff.AddedEggs {
+		added = append(added, shinyMark(e.CanBeShiny)+"**"+e.Name+"** ("+eggLabel(e)+")")
+	}
+	for _, e := range diff.RemovedEggs {
+		removed = append(removed, "**"+e.Name+"** ("+eggLabel(e)+")")
+	}
+	addField("New in eggs", added)
+	addField("Left eggs", removed)
+
+	added, removed = nil, nil
+	for _, r := range diff.AddedResearch {
+		added = append(added, shinyMark(r.Reward.CanBeShiny)+"**"+r.Reward.Name+"** - "+r.Task)
+	}
+	for _, r := range diff.RemovedResearch {
+		removed = append(removed, "**"+r.Reward.Name+"** - "+r.Task)
+	}
+	addField("New research rewards", added)
+	addField("Removed research rewards", removed)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var debuts []string
+	for _, debut := range diff.NewShinies {
+		debuts = append(debuts, "✨ **"+debut.Name+"** - "+debut.Source)
+	}
+	addField("Shiny debuts", debuts)
+
+	return &discordgo.MessageEmbed{
+		Title:       "Raid, egg and research rotation changed",
+		Description: "✨ marks pokemon that can be shiny.",
+		Color:       0xF2C94C,
+		Fields:      fields,
+		Timestamp:   diff.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// func to prefix shiny eligible pokemon
+func shinyMark(shiny bool) string {
+	if shiny {
+		return "✨ "
+	}
+	return ""
+}
+
+// func to describe which egg a pokemon hatches from
+func eggLabel(e Egg) string {
+	if e.IsAdventureSync {
+		return e.EggType + " Adventure Sync"
+	}
+	return e.EggType
+}
+
+// func to join lines up to a max length, the rest is counted in a final line
+func joinLines(lines []string, limit int) string {
+	msg := ""
+	for i, line := range lines {
+		more := "…and " + strconv.Itoa(len(lines)-i) + " more"
+		if len(msg)+len(line)+len(more)+2 > limit {
+			return msg + more
+		}
+		msg = msg + line + "\n"
+	}
+	return msg
+}
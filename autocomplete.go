@@ -0,0 +1,73 @@
+// autocomplete.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discord shows at most this many suggestions
+const maxSuggestions = 25
+
+// discord drops suggestions that take longer than this
+const autocompleteTimeout = 3 * time.Second
+
+// func to suggest pokemon names or types for the option the user is typing in
+func handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, st Store, data *Refresher) {
+	input := i.ApplicationCommandData()
+
+	// find the option being typed
+	var focused *discordgo.ApplicationCommandInteractionDataOption
+	for _, opt := range input.Options {
+		if opt.Focused {
+			focused = opt
+		}
+	}
+	cmd, ok := registry.Lookup(input.Name)
+	if focused == nil || !ok {
+		return
+	}
+
+	// the command picks the candidates, they can depend on the other options
+	var candidates []string
+	if ac, ok := cmd.(AutocompleteCommand); ok {
+		ctx := discordContext(st, data, s, i, nil)
+		var cancel context.CancelFunc
+		ctx.Ctx, cancel = context.WithTimeout(ctx.Ctx, autocompleteTimeout)
+		var err error
+		candidates, err = ac.Candidates(ctx, focused.Name)
+		cancel()
+		var unavailable *DataUnavailableError
+		switch {
+		case errors.As(err, &unavailable):
+			// asked on every key press, the missing data is already warned about on startup
+			slog.Debug("No autocomplete candidates", "command", input.Name, "option", focused.Name, "err", err)
+		case err != nil:
+			slog.Error("Error loading autocomplete candidates", "command", input.Name, "option", focused.Name, "err", err)
+		}
+	}
+
+	// an empty list is still sent so discord stops waiting for suggestions
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, name := range resolver.Match(focused.StringValue(), candidates, maxSuggestions) {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+	if err != nil {
+		slog.Error("Error sending autocomplete choices", "command", input.Name, "err", err)
+	}
+}
@@ -0,0 +1,252 @@
+// cli.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// first arguments that run a command in the terminal instead of starting the bot
+var cliNames = []string{"query", "cli"}
+
+// func to check if the arguments ask for the terminal
+func isCLI(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	for _, name := range cliNames {
+		if args[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// func to run one command with the same handler discord uses and print the reply, returns the exit code
+// usage: bot query [--refresh] [--guild id] <command> [values...] [--option value...]
+func runCLI(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	refresh := flags.Bool("refresh", false, "pull the latest data before running the command")
+	guild := flags.String("guild", "", "server id to run the command as if it was run there")
+	flags.Usage = func() {
+		printUsage(stderr, flags)
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || flags.Arg(0) == "help" {
+		flags.Usage()
+		return 2
+	}
+
+	name := strings.TrimPrefix(flags.Arg(0), "/")
+	cmd, ok := registry.Lookup(name)
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command %q\n", name)
+		flags.Usage()
+		return 2
+	}
+	def := cmd.Definition()
+	options, err := parseOptions(def, flags.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		fmt.Fprintln(stderr, optionUsage(def))
+		return 2
+	}
+
+	// without --refresh the command runs against the data already in the database
+	if *refresh {
+		_, err = refresher.Refresh()
+		if err != nil {
+			fmt.Fprintln(stderr, "Error refreshing data, using the previous data:", err)
+		}
+	} else if !refresher.UseSnapshotTime() {
+		fmt.Fprintln(stderr, "No data was imported yet, add --refresh to pull it")
+	}
+
+	ctx := newContext(store, refresher, def.Name, *guild, options, &printer{w: stdout, data: refresher})
+	err = cmd.Handle(ctx)
+	if err != nil {
+		logCommandError(ctx.Logger, err)
+		fmt.Fprintln(stderr, errorReply(err))
+		return 1
+	}
+
+	// nothing runs the background refresh loop here, so do a refresh the command queued before exiting
+	_, err = refresher.RunQueued()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error refreshing data:", err)
+		return 1
+	}
+	return 0
+}
+
+// func to print how to run commands in the terminal
+func printUsage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: bot query [--refresh] [--guild id] <command> [values...] [--option value...]")
+	fmt.Fprintln(w, "Values without an option name fill the options in order, eg. bot query hundo mewtwo")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	flags.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, def := range registry.Definitions() {
+		fmt.Fprintf(w, "  %-10s %s\n", def.Name, def.Description)
+	}
+}
+
+// func to describe the options of a command, eg. "Usage: raids --raid_tier <tier 1|tier 3|...>"
+func optionUsage(def *discordgo.ApplicationCommand) string {
+	usage := "Usage: " + def.Name
+	for _, opt := range def.Options {
+		value := strings.ToLower(opt.Type.String())
+		if len(opt.Choices) > 0 {
+			var values []string
+			for _, choice := range opt.Choices {
+				values = append(values, choiceValue(choice.Value))
+			}
+			value = strings.Join(values, "|")
+		}
+		part := "--" + opt.Name + " <" + value + ">"
+		if !opt.Required {
+			part = "[" + part + "]"
+		}
+		usage = usage + " " + part
+	}
+	return usage
+}
+
+// func to turn terminal arguments into the option values of a command
+// "--name value", "--name=value" and values in option order are accepted
+func parseOptions(def *discordgo.ApplicationCommand, args []string) (map[string]any, error) {
+	values := map[string]string{}
+	var positional []string
+	for n := 0; n < len(args); n++ {
+		arg := args[n]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !hasValue {
+			if n+1 >= len(args) {
+				return nil, fmt.Errorf("--%s needs a value", name)
+			}
+			n++
+			value = args[n]
+		}
+		opt, err := findOption(def, name)
+		if err != nil {
+			return nil, err
+		}
+		values[opt.Name] = value
+	}
+
+	// values without a name fill the options that were not named, in order
+	for _, opt := range def.Options {
+		if len(positional) == 0 {
+			break
+		}
+		if _, ok := values[opt.Name]; !ok {
+			values[opt.Name] = positional[0]
+			positional = positional[1:]
+		}
+	}
+	if len(positional) > 0 {
+		return nil, fmt.Errorf("too many values for %s: %s", def.Name, strings.Join(positional, " "))
+	}
+
+	options := map[string]any{}
+	var errs []error
+	for _, opt := range def.Options {
+		value, ok := values[opt.Name]
+		if !ok {
+			if opt.Required {
+				errs = append(errs, fmt.Errorf("--%s is required", opt.Name))
+			}
+			continue
+		}
+		parsed, err := optionValue(opt, value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		options[opt.Name] = parsed
+	}
+	return options, errors.Join(errs...)
+}
+
+// func to find the option a flag names, the end or start of an option name is enough
+// eg. "--tier" finds "raid_tier" and "--current" finds "current_xp"
+func findOption(def *discordgo.ApplicationCommand, flag string) (*discordgo.ApplicationCommandOption, error) {
+	flag = strings.ReplaceAll(strings.ToLower(flag), "-", "_")
+	var matches []*discordgo.ApplicationCommandOption
+	for _, opt := range def.Options {
+		if opt.Name == flag {
+			return opt, nil
+		}
+		if strings.HasSuffix(opt.Name, "_"+flag) || strings.HasPrefix(opt.Name, flag+"_") {
+			matches = append(matches, opt)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%s has no option --%s", def.Name, flag)
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, opt := range matches {
+		names = append(names, "--"+opt.Name)
+	}
+	return nil, fmt.Errorf("--%s could be %s", flag, strings.Join(names, " or "))
+}
+
+// func to check and convert a value the same way discordContext does for the option
+// values of options with choices can be given as the value or the label, eg. "tier 5" or "Tier 5"
+func optionValue(opt *discordgo.ApplicationCommandOption, value string) (any, error) {
+	if len(opt.Choices) > 0 {
+		found := false
+		var values []string
+		for _, choice := range opt.Choices {
+			v := choiceValue(choice.Value)
+			if strings.EqualFold(value, v) || strings.EqualFold(value, choice.Name) {
+				value = v
+				found = true
+				break
+			}
+			values = append(values, v)
+		}
+		if !found {
+			return nil, fmt.Errorf("--%s must be one of %s", opt.Name, strings.Join(values, ", "))
+		}
+	}
+
+	switch opt.Type {
+	case discordgo.ApplicationCommandOptionInteger:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("--%s must be a whole number", opt.Name)
+		}
+		return n, nil
+	case discordgo.ApplicationCommandOptionChannel:
+		if !isSnowflake(value) {
+			return nil, fmt.Errorf("--%s must be a channel id", opt.Name)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,63 @@
+// cli_test.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	def := xpCommand{}.Definition()
+	tests := []struct {
+		args []string
+		want map[string]any
+		err  string
+	}{
+		{[]string{"1000000", "20000000"}, map[string]any{"current_xp": int64(1000000), "xp_goal": int64(20000000)}, ""},
+		{[]string{"--goal", "20000000", "--current=1000000"}, map[string]any{"current_xp": int64(1000000), "xp_goal": int64(20000000)}, ""},
+		{[]string{"1000000", "level 40"}, map[string]any{"current_xp": int64(1000000), "xp_goal": int64(20000000)}, ""},
+		{[]string{"1000000", "level 60"}, nil, "--xp_goal must be one of"},
+		{[]string{"a lot", "20000000"}, nil, "--current_xp must be a whole number"},
+		{[]string{"1000000"}, nil, "--xp_goal is required"},
+	}
+	for _, test := range tests {
+		got, err := parseOptions(def, test.args)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("parseOptions(%q) error = %v, want %q", test.args, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseOptions(%q) error = %v", test.args, err)
+			continue
+		}
+		for name, value := range test.want {
+			if got[name] != value {
+				t.Errorf("parseOptions(%q)[%s] = %#v, want %#v", test.args, name, got[name], value)
+			}
+		}
+	}
+}
+
+func TestXpCommandInTerminal(t *testing.T) {
+	options, err := parseOptions(xpCommand{}.Definition(), []string{"1000000", "20000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	err = xpCommand{}.Handle(newContext(nil, nil, "xp", "", options, &printer{w: &out}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Xp to Level 40: 19000000  |  Percent of xp gained: 5.00%\n"
+	if out.String() != want {
+		t.Errorf("xp printed %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,197 @@
+// commands.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// interface for a slash command, the definition sent to discord and the handler that runs it are kept together
+type Command interface {
+	// the name, description and options shown in discord
+	Definition() *discordgo.ApplicationCommand
+	// runs the command and replies through the context, a returned error is shown to the user instead
+	Handle(ctx *Context) error
+}
+
+// interface for commands whose replies only the user who ran them can see
+type EphemeralCommand interface {
+	Command
+	Ephemeral() bool
+}
+
+// interface for commands with options that suggest values while the user types
+type AutocompleteCommand interface {
+	Command
+	// gets every value the option can be set to, they are matched against what was typed
+	Candidates(ctx *Context, option string) ([]string, error)
+}
+
+// interface for the frontend showing the reply of a command, discord builds embeds and the terminal prints text
+// commands pass it plain results, each call is the whole reply
+type Responder interface {
+	Text(msg string)
+	Best(result *BestResult)
+	Events(when string, events []Event)
+	Hundo(result *HundoResult)
+	Eggs(eggs []Egg)
+	Raids(raids []Raid)
+	Research(research []ResearchReward)
+	Where(pokemon string, sources []PokemonSource)
+	Changes(diff *DatasetDiff)
+	Xp(progress XpProgress)
+}
+
+// struct passed to a command handler with everything it needs
+// handlers only use the session and interaction for discord specific work, both are nil in the terminal
+type Context struct {
+	// cancelled when the command times out, pass it to every store call so slow queries stop with it
+	Ctx         context.Context
+	Session     *discordgo.Session
+	Interaction *discordgo.InteractionCreate
+	GuildID     string // server the command was run in, empty in dms and the terminal
+	Store       Store
+	Data        *Refresher // how fresh the data in the store is, nil if nothing refreshes it
+	Logger      *slog.Logger
+	Out         Responder // where the reply goes, nil for autocomplete, those answer with suggestions instead
+
+	// option values by name, strings for string and channel options and int64 for integer options
+	options map[string]any
+}
+
+// func to create the context for a command run against the store
+// data is the refresher keeping the store up to date, nil if nothing does
+func newContext(st Store, data *Refresher, name string, guildID string, options map[string]any, out Responder) *Context {
+	return &Context{
+		Ctx:     context.Background(),
+		GuildID: guildID,
+		Store:   st,
+		Data:    data,
+		Logger:  slog.With("command", name, "guild", guildID),
+		Out:     out,
+		options: options,
+	}
+}
+
+// func to create the context for an interaction with a command in discord
+func discordContext(st Store, data *Refresher, s *discordgo.Session, i *discordgo.InteractionCreate, out Responder) *Context {
+	cmd := i.ApplicationCommandData()
+	ctx := newContext(st, data, cmd.Name, i.GuildID, optionValues(cmd.Options), out)
+	ctx.Session = s
+	ctx.Interaction = i
+	return ctx
+}
+
+// func to get the values of the options discord sent by name
+func optionValues(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]any {
+	values := make(map[string]any, len(options))
+	for _, opt := range options {
+		switch opt.Type {
+		case discordgo.ApplicationCommandOptionString:
+			values[opt.Name] = opt.StringValue()
+		case discordgo.ApplicationCommandOptionInteger:
+			values[opt.Name] = opt.IntValue()
+		case discordgo.ApplicationCommandOptionChannel:
+			values[opt.Name] = opt.ChannelValue(nil).ID
+		default:
+			values[opt.Name] = opt.Value
+		}
+	}
+	return values
+}
+
+// func to check if the user set an option
+func (c *Context) Has(name string) bool {
+	_, ok := c.options[name]
+	return ok
+}
+
+// func to get a string option, empty if it was not set
+func (c *Context) String(name string) string {
+	value, _ := c.options[name].(string)
+	return value
+}
+
+// func to get an integer option, 0 if it was not set
+func (c *Context) Int(name string) int64 {
+	value, _ := c.options[name].(int64)
+	return value
+}
+
+// func to get the id of a channel option, empty if it was not set
+func (c *Context) ChannelID(name string) string {
+	return c.String(name)
+}
+
+// func to reply with plain text
+func (c *Context) Reply(content string) {
+	c.Out.Text(content)
+}
+
+// struct holding the commands of the bot by name
+type Registry struct {
+	commands map[string]Command
+	order    []Command // in the order they were registered, discord lists them like that
+}
+
+// func to create a registry holding the given commands
+func NewRegistry(cmds ...Command) (*Registry, error) {
+	r := &Registry{commands: map[string]Command{}}
+	var errs []error
+	for _, cmd := range cmds {
+		errs = append(errs, r.Register(cmd))
+	}
+	return r, errors.Join(errs...)
+}
+
+// func to add a command, its name has to be unique
+func (r *Registry) Register(cmd Command) error {
+	def := cmd.Definition()
+	if def.Name == "" {
+		return fmt.Errorf("command %T has no name", cmd)
+	}
+	if _, ok := r.commands[def.Name]; ok {
+		return fmt.Errorf("command %q is registered twice", def.Name)
+	}
+	// discord asks for suggestions on these options, the command has to be able to give them
+	if _, ok := cmd.(AutocompleteCommand); !ok {
+		for _, opt := range def.Options {
+			if opt.Autocomplete {
+				return fmt.Errorf("command %q option %q has autocomplete but the command gives no candidates", def.Name, opt.Name)
+			}
+		}
+	}
+
+	r.commands[def.Name] = cmd
+	r.order = append(r.order, cmd)
+	return nil
+}
+
+// func to find a command by name
+func (r *Registry) Lookup(name string) (Command, bool) {
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
+
+// func to get the definitions of every command to send to discord
+func (r *Registry) Definitions() []*discordgo.ApplicationCommand {
+	defs := make([]*discordgo.ApplicationCommand, 0, len(r.order))
+	for _, cmd := range r.order {
+		defs = append(defs, cmd.Definition())
+	}
+	return defs
+}
+
+// func to check if a command replies so only the user who ran it can see
+func isEphemeral(cmd Command) bool {
+	e, ok := cmd.(EphemeralCommand)
+	return ok && e.Ephemeral()
+}
@@ -0,0 +1,192 @@
+// commandsync.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// struct for the commands a sync adds, changes and removes in one scope
+type commandDiff struct {
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+// func to check if a scope is already up to date
+func (d commandDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// func to bring the registered commands of each scope in line with the definitions
+// a scope is a guild id or "" for global, global commands are removed when global is not a scope
+// scopes that are already up to date are left alone so restarts don't hit the rate limits
+func syncCommands(s *discordgo.Session, appID string, scopes []string, defs []*discordgo.ApplicationCommand) error {
+	desired := map[string][]*discordgo.ApplicationCommand{"": {}}
+	for _, scope := range scopes {
+		desired[scope] = defs
+	}
+
+	var errs []error
+	for scope, want := range desired {
+		errs = append(errs, syncScope(s, appID, scope, want))
+	}
+	return errors.Join(errs...)
+}
+
+// func to sync the commands of one scope, using a single bulk overwrite when anything differs
+func syncScope(s *discordgo.Session, appID string, scope string, want []*discordgo.ApplicationCommand) error {
+	existing, err := s.ApplicationCommands(appID, scope)
+	if err != nil {
+		return fmt.Errorf("error listing commands of %s: %w", scopeName(scope), err)
+	}
+
+	diff := diffCommands(existing, want)
+	if diff.empty() {
+		slog.Debug("Commands are up to date", "scope", scopeName(scope))
+		return nil
+	}
+
+	_, err = s.ApplicationCommandBulkOverwrite(appID, scope, want)
+	if err != nil {
+		return fmt.Errorf("error overwriting commands of %s: %w", scopeName(scope), err)
+	}
+	slog.Info("Synced commands", "scope", scopeName(scope), "added", diff.Added, "changed", diff.Changed, "removed", diff.Removed)
+	return nil
+}
+
+// func to remove every command of the given scopes, used on shutdown when cleanup is on
+func removeCommands(s *discordgo.Session, appID string, scopes []string) error {
+	var errs []error
+	for _, scope := range scopes {
+		_, err := s.ApplicationCommandBulkOverwrite(appID, scope, []*discordgo.ApplicationCommand{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error removing commands of %s: %w", scopeName(scope), err))
+			continue
+		}
+		slog.Info("Removed commands", "scope", scopeName(scope))
+	}
+	return errors.Join(errs...)
+}
+
+// func to compare the registered commands against the wanted ones by name
+func diffCommands(existing []*discordgo.ApplicationCommand, want []*discordgo.ApplicationCommand) commandDiff {
+	current := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, cmd := range existing {
+		current[cmd.Name] = cmd
+	}
+
+	var diff commandDiff
+	for _, cmd := range want {
+		old, ok := current[cmd.Name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, cmd.Name)
+		case !reflect.DeepEqual(shapeOf(old), shapeOf(cmd)):
+			diff.Changed = append(diff.Changed, cmd.Name)
+		}
+		delete(current, cmd.Name)
+	}
+	for _, cmd := range existing {
+		if _, ok := current[cmd.Name]; ok {
+			diff.Removed = append(diff.Removed, cmd.Name)
+		}
+	}
+	return diff
+}
+
+// struct for the parts of a command that are compared, discord fills in defaults and ids we don't set
+type commandShape struct {
+	Type        discordgo.ApplicationCommandType
+	Name        string
+	Description string
+	Permissions string
+	DMs         bool
+	Options     []optionShape
+}
+
+// struct for the parts of an option that are compared
+type optionShape struct {
+	Type         discordgo.ApplicationCommandOptionType
+	Name         string
+	Description  string
+	Required     bool
+	Autocomplete bool
+	ChannelTypes []discordgo.ChannelType
+	Choices      [][2]string
+	Options      []optionShape
+}
+
+// func to get the comparable shape of a command
+func shapeOf(cmd *discordgo.ApplicationCommand) commandShape {
+	shape := commandShape{
+		Type:        cmd.Type,
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		DMs:         true,
+		Options:     optionShapes(cmd.Options),
+	}
+	// commands can be used in dms unless that is turned off
+	if cmd.DMPermission != nil {
+		shape.DMs = *cmd.DMPermission
+	}
+	// commands without a type are chat commands
+	if shape.Type == 0 {
+		shape.Type = discordgo.ChatApplicationCommand
+	}
+	if cmd.DefaultMemberPermissions != nil {
+		shape.Permissions = strconv.FormatInt(*cmd.DefaultMemberPermissions, 10)
+	}
+	return shape
+}
+
+// func to get the comparable shapes of a list of options
+func optionShapes(options []*discordgo.ApplicationCommandOption) []optionShape {
+	var shapes []optionShape
+	for _, opt := range options {
+		shape := optionShape{
+			Type:         opt.Type,
+			Name:         opt.Name,
+			Description:  opt.Description,
+			Required:     opt.Required,
+			Autocomplete: opt.Autocomplete,
+			Options:      optionShapes(opt.Options),
+		}
+		if len(opt.ChannelTypes) > 0 {
+			shape.ChannelTypes = opt.ChannelTypes
+		}
+		for _, choice := range opt.Choices {
+			shape.Choices = append(shape.Choices, [2]string{choice.Name, choiceValue(choice.Value)})
+		}
+		shapes = append(shapes, shape)
+	}
+	return shapes
+}
+
+// func to format a choice value, discord sends numbers back as floats, eg. "20000000" comes back as 2e+07
+func choiceValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// func to describe a scope for log messages
+func scopeName(scope string) string {
+	if scope == "" {
+		return "global"
+	}
+	return "guild " + scope
+}
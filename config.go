@@ -0,0 +1,264 @@
+// config.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// config file read when BOT_CONFIG is not set, it is optional
+const defaultConfigPath = "config.json"
+
+// database used for each backend when no dsn is set
+var defaultDSN = map[string]string{
+	"mysql":  "root:mysql@tcp(127.0.0.1:3306)/pogodb",
+	"sqlite": "./pogodb.sqlite",
+}
+
+// struct holding every setting of the bot
+// values come from the defaults, then the config file, then the environment variables
+type Config struct {
+	Token           string   `json:"token"`            // BOT_TOKEN, discord bot token
+	Guilds          []string `json:"guilds"`           // BOT_GUILDS, comma separated server ids, empty registers the commands globally
+	GlobalCommands  bool     `json:"global_commands"`  // BOT_GLOBAL_COMMANDS, also register the commands globally when guilds are set
+	RemoveCommands  bool     `json:"remove_commands"`  // BOT_REMOVE_COMMANDS, remove the commands again when the bot stops
+	DatabaseType    string   `json:"db_type"`          // BOT_DB_TYPE, mysql | sqlite
+	DatabaseDSN     string   `json:"db_dsn"`           // BOT_DB_DSN, mysql connection string or sqlite file path, empty uses the local default
+	DataSource      string   `json:"data_source"`      // BOT_DATA_SOURCE, http | git | dir
+	DataURL         string   `json:"data_url"`         // BOT_DATA_URL, repo url for git, base url for http, empty uses ScrapedDuck
+	DataBranch      string   `json:"data_branch"`      // BOT_DATA_BRANCH, branch holding the json files
+	DataPath        string   `json:"data_path"`        // BOT_DATA_PATH, folder the json files are stored in or read from
+	RefreshInterval Duration `json:"refresh_interval"` // BOT_REFRESH_INTERVAL, eg. "30m"
+	CommandTimeout  Duration `json:"command_timeout"`  // BOT_COMMAND_TIMEOUT, how long a command can take before it fails, eg. "10s"
+	LogLevel        string   `json:"log_level"`        // BOT_LOG_LEVEL, debug | info | warn | error
+
+	// nicknames mapped to the pokemon they stand for, eg. {"ttar": "Tyranitar"}, config file only
+	Aliases map[string]string `json:"aliases"`
+}
+
+// duration that reads from json as a string like "30m" or "1h"
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+	d.Duration, err = time.ParseDuration(value)
+	return err
+}
+
+// func to get the config used when nothing is set
+func defaultConfig() *Config {
+	return &Config{
+		DatabaseType:    "mysql",
+		DataSource:      "http",
+		DataBranch:      defaultBranch,
+		DataPath:        "./data",
+		RefreshInterval: Duration{30 * time.Minute},
+		CommandTimeout:  Duration{10 * time.Second},
+		LogLevel:        "info",
+	}
+}
+
+// func to load and validate the config, offline is for the terminal where no token is needed
+func loadConfig(offline bool) (*Config, error) {
+	cfg := defaultConfig()
+
+	// the default file is optional, a file named in BOT_CONFIG has to exist
+	path, named := os.LookupEnv("BOT_CONFIG")
+	if !named {
+		path = defaultConfigPath
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && (named || !errors.Is(err, os.ErrNotExist)) {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+
+	err = cfg.loadEnv()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DatabaseDSN == "" {
+		cfg.DatabaseDSN = defaultDSN[cfg.DatabaseType]
+	}
+	err = cfg.validate(offline)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// func to override the config with the environment variables that are set
+func (c *Config) loadEnv() error {
+	strs := map[string]*string{
+		"BOT_TOKEN":       &c.Token,
+		"BOT_DB_TYPE":     &c.DatabaseType,
+		"BOT_DB_DSN":      &c.DatabaseDSN,
+		"BOT_DATA_SOURCE": &c.DataSource,
+		"BOT_DATA_URL":    &c.DataURL,
+		"BOT_DATA_BRANCH": &c.DataBranch,
+		"BOT_DATA_PATH":   &c.DataPath,
+		"BOT_LOG_LEVEL":   &c.LogLevel,
+	}
+	for name, field := range strs {
+		if value, ok := os.LookupEnv(name); ok {
+			*field = strings.TrimSpace(value)
+		}
+	}
+
+	if value, ok := os.LookupEnv("BOT_GUILDS"); ok {
+		c.Guilds = nil
+		for _, guild := range strings.Split(value, ",") {
+			if guild = strings.TrimSpace(guild); guild != "" {
+				c.Guilds = append(c.Guilds, guild)
+			}
+		}
+	}
+	bools := map[string]*bool{
+		"BOT_GLOBAL_COMMANDS": &c.GlobalCommands,
+		"BOT_REMOVE_COMMANDS": &c.RemoveCommands,
+	}
+	for name, field := range bools {
+		if value, ok := os.LookupEnv(name); ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*field = b
+		}
+	}
+
+	durations := map[string]*Duration{
+		"BOT_REFRESH_INTERVAL": &c.RefreshInterval,
+		"BOT_COMMAND_TIMEOUT":  &c.CommandTimeout,
+	}
+	for name, field := range durations {
+		if value, ok := os.LookupEnv(name); ok {
+			d, err := time.ParseDuration(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+			field.Duration = d
+		}
+	}
+	return nil
+}
+
+// func to check every setting, all problems are reported at once
+func (c *Config) validate(offline bool) error {
+	var errs []error
+	if c.Token == "" && !offline {
+		errs = append(errs, errors.New("token is required, set BOT_TOKEN or \"token\" in the config file"))
+	}
+	for _, guild := range c.Guilds {
+		if !isSnowflake(guild) {
+			errs = append(errs, fmt.Errorf("guild %q is not a discord server id", guild))
+		}
+	}
+
+	switch c.DatabaseType {
+	case "mysql", "sqlite":
+	default:
+		errs = append(errs, fmt.Errorf("db_type %q must be mysql or sqlite", c.DatabaseType))
+	}
+	if c.DatabaseDSN == "" {
+		errs = append(errs, errors.New("db_dsn is required"))
+	}
+
+	switch c.DataSource {
+	case "http", "git":
+		if c.DataURL != "" {
+			u, err := url.Parse(c.DataURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Errorf("data_url %q must be an http or https url", c.DataURL))
+			}
+		}
+		if c.DataBranch == "" {
+			errs = append(errs, errors.New("data_branch is required"))
+		}
+	case "dir":
+	default:
+		errs = append(errs, fmt.Errorf("data_source %q must be http, git or dir", c.DataSource))
+	}
+	if c.DataPath == "" {
+		errs = append(errs, errors.New("data_path is required"))
+	}
+
+	if c.RefreshInterval.Duration < time.Minute {
+		errs = append(errs, fmt.Errorf("refresh_interval %v must be at least 1m", c.RefreshInterval.Duration))
+	}
+	// discord only accepts the reply to a command for 15 minutes
+	if c.CommandTimeout.Duration < time.Second || c.CommandTimeout.Duration > 14*time.Minute {
+		errs = append(errs, fmt.Errorf("command_timeout %v must be between 1s and 14m", c.CommandTimeout.Duration))
+	}
+	for alias, name := range c.Aliases {
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(name) == "" {
+			errs = append(errs, fmt.Errorf("alias %q for %q needs both a nickname and a pokemon name", alias, name))
+		}
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		errs = append(errs, fmt.Errorf("log_level %q must be debug, info, warn or error", c.LogLevel))
+	}
+	return errors.Join(errs...)
+}
+
+// func to send all logs through slog, dropping the ones below the configured level
+func (c *Config) setupLogging() {
+	var level slog.Level
+	// already checked by validate
+	level.UnmarshalText([]byte(c.LogLevel))
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+}
+
+// func to get where the commands are registered, a guild id or "" for global
+func (c *Config) CommandScopes() []string {
+	scopes := append([]string{}, c.Guilds...)
+	if len(scopes) == 0 || c.GlobalCommands {
+		scopes = append(scopes, "")
+	}
+	return scopes
+}
+
+// func to get the token in the form discord expects
+func (c *Config) BotToken() string {
+	if strings.HasPrefix(c.Token, "Bot ") {
+		return c.Token
+	}
+	return "Bot " + c.Token
+}
+
+// func to check a discord id, they are numbers of up to 20 digits
+func isSnowflake(id string) bool {
+	if len(id) == 0 || len(id) > 20 {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
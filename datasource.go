@@ -0,0 +1,134 @@
+// datasource.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// default locations of the ScrapedDuck data
+const (
+	defaultRepoURL = "https://github.com/bigfoott/ScrapedDuck.git"
+	defaultRawURL  = "https://raw.githubusercontent.com/bigfoott/ScrapedDuck/" // followed by the branch name
+	defaultBranch  = "data"
+)
+
+// interface for anything that can provide the ScrapedDuck json files
+type DataSource interface {
+	// pull the latest files, returns true if anything changed
+	Sync() (bool, error)
+	// read one of the json files by name, eg. "eggs.json"
+	ReadFile(name string) ([]byte, error)
+	// describe the source for log messages
+	String() string
+}
+
+// func to create the data source for the given type
+// location is a repo url for git or a base url for http, empty uses ScrapedDuck
+// path is the folder the files are stored in, or read from for dir
+func newDataSource(sourceType string, location string, branch string, path string) (DataSource, error) {
+	switch sourceType {
+	case "git":
+		if location == "" {
+			location = defaultRepoURL
+		}
+		return &GitSource{RepoURL: location, Branch: branch, ClonePath: path, OutputPath: "./ScrapedDuck"}, nil
+	case "http", "":
+		// a custom base url already points at the branch
+		if location == "" {
+			location = defaultRawURL + branch
+		}
+		return &HTTPSource{Fetcher: NewHTTPFetcher(location, path)}, nil
+	case "dir":
+		return &DirSource{Path: path}, nil
+	}
+	return nil, fmt.Errorf("unknown data source type %q", sourceType)
+}
+
+// data source that clones the ScrapedDuck repo with git
+type GitSource struct {
+	RepoURL    string
+	Branch     string
+	ClonePath  string
+	OutputPath string
+}
+
+// func to re-clone the repo, always reports a change
+func (g *GitSource) Sync() (bool, error) {
+	// delete old files
+	os.RemoveAll(g.ClonePath)
+	os.RemoveAll(g.OutputPath)
+	os.Mkdir(g.OutputPath, os.ModePerm)
+
+	// clone repo
+	err := CloneRepo(g.RepoURL, g.ClonePath)
+	if err != nil {
+		return false, err
+	}
+
+	// copy files from the repo
+	err = CopyFilesFromBranch(g.ClonePath, g.Branch, g.OutputPath)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// func to read a file from the checked out branch
+func (g *GitSource) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(g.ClonePath, name))
+}
+
+func (g *GitSource) String() string {
+	return "git " + g.RepoURL + " (" + g.Branch + ")"
+}
+
+// data source that downloads the json files over http
+type HTTPSource struct {
+	Fetcher *HTTPFetcher
+}
+
+// func to download the files that changed since the last sync
+func (h *HTTPSource) Sync() (bool, error) {
+	return h.Fetcher.Fetch()
+}
+
+// func to read a downloaded file
+func (h *HTTPSource) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(h.Fetcher.Dir, name))
+}
+
+func (h *HTTPSource) String() string {
+	return "http " + h.Fetcher.BaseURL
+}
+
+// data source that reads the json files from a local folder, eg. a mirror or test fixtures
+type DirSource struct {
+	Path string
+}
+
+// func to check the folder is there, always reports a change since edits are not tracked
+func (d *DirSource) Sync() (bool, error) {
+	info, err := os.Stat(d.Path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open data folder: %w", err)
+	}
+	if !info.IsDir() {
+		return false, fmt.Errorf("data path %s is not a folder", d.Path)
+	}
+	return true, nil
+}
+
+// func to read a file from the folder
+func (d *DirSource) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(d.Path, name))
+}
+
+func (d *DirSource) String() string {
+	return "dir " + d.Path
+}
@@ -0,0 +1,183 @@
+// discord.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// func to reply with plain text
+func (r *reply) Text(msg string) {
+	respondPages(r, []page{{content: msg}})
+}
+
+// func to reply with the best attackers ranking
+func (r *reply) Best(result *BestResult) {
+	r.embeds([]*discordgo.MessageEmbed{bestEmbed(result)})
+}
+
+// func to reply with the live or upcoming events
+func (r *reply) Events(when string, events []Event) {
+	r.text(eventsText(when, events))
+}
+
+// func to reply with the hundo cp of a pokemon
+func (r *reply) Hundo(result *HundoResult) {
+	r.embeds([]*discordgo.MessageEmbed{hundoEmbed(result)})
+}
+
+// func to reply with the pokemon hatching from eggs, one embed per pokemon so each gets its sprite
+func (r *reply) Eggs(eggs []Egg) {
+	embeds := eggEmbeds(eggs)
+	if len(eggs) == 0 {
+		embeds = []*discordgo.MessageEmbed{{Title: "Eggs", Description: "No eggs found.", Color: colorEggs}}
+	}
+	r.embeds(embeds)
+}
+
+// func to reply with the raid bosses, one embed per boss so each gets its sprite
+func (r *reply) Raids(raids []Raid) {
+	embeds := raidEmbeds(raids)
+	if len(raids) == 0 {
+		embeds = []*discordgo.MessageEmbed{{Title: "Raids", Description: "No raids found.", Color: colorRaids}}
+	}
+	r.embeds(embeds)
+}
+
+// func to reply with research tasks
+func (r *reply) Research(research []ResearchReward) {
+	r.text(researchText(research))
+}
+
+// func to reply with the current sources of a pokemon
+func (r *reply) Where(pokemon string, sources []PokemonSource) {
+	r.text(whereText(pokemon, sources))
+}
+
+// func to reply with the raid, egg and research changes, the same embed the announcements use
+func (r *reply) Changes(diff *DatasetDiff) {
+	respondPages(r, embedPages([]*discordgo.MessageEmbed{buildChangeEmbed(diff)}))
+}
+
+// func to reply with the progress towards an xp goal
+func (r *reply) Xp(progress XpProgress) {
+	r.Text("Xp to " + progress.Level + ": **" + strconv.Itoa(int(progress.Remaining)) + "**  |  Percent of xp gained: **" + fmt.Sprintf("%.2f", progress.Percent) + "%**")
+}
+
+// func to reply with text split into pages, with a warning on each when the data is stale
+func (r *reply) text(msg string) {
+	respondPages(r, textPages(msg, staleNotice(r.data)))
+}
+
+// func to reply with embeds split into pages, with the data freshness on each
+func (r *reply) embeds(embeds []*discordgo.MessageEmbed) {
+	pages := embedPages(embeds)
+	for _, p := range pages {
+		addFreshness(r.data, p.embeds)
+	}
+	respondPages(r, pages)
+}
+
+// func to build the list of events
+func eventsText(when string, events []Event) string {
+	// create header and then format each event
+	msg := "**Live events:**\n"
+	if when == "week" {
+		msg = "**Events in the next 7 days:**\n"
+	}
+
+	for _, e := range events {
+		msg = msg + "**" + e.Name + "** (" + e.Heading + ")\nStarts: " + discordTime(e.Start) + "  |  Ends: " + discordTime(e.End) + "\n<" + e.Link + ">\n\n"
+	}
+	if len(events) == 0 {
+		msg = msg + "No events found."
+	}
+	return msg
+}
+
+// func to build the list of research tasks with their rewards
+func researchText(research []ResearchReward) string {
+	// create header and then list the rewards under each task
+	msg := "**Research:**\n"
+	last_task := ""
+
+	for _, r := range research {
+		if r.Task != last_task {
+			msg = msg + "**" + r.Task + "** (" + r.Type + ")\n"
+			last_task = r.Task
+		}
+		msg = msg + "  " + r.Reward.Name + "   cp: **" + strconv.Itoa(r.Reward.CombatPower.Min) + "-" + strconv.Itoa(r.Reward.CombatPower.Max) +
+			"**   Shiny: " + convertBool(r.Reward.CanBeShiny) + "\n"
+	}
+	if len(research) == 0 {
+		msg = msg + "No research tasks found."
+	}
+	return msg
+}
+
+// func to build the list of sources of a pokemon
+func whereText(pokemon string, sources []PokemonSource) string {
+	// group the sources under a header for each kind
+	headers := map[string]string{"egg": "**Eggs:**\n", "raid": "**Raids:**\n", "research": "**Research:**\n", "event": "**Events:**\n"}
+	msg := "**Where to find " + pokemon + ":**\n"
+	last_kind := ""
+
+	for _, src := range sources {
+		if src.Kind != last_kind {
+			msg = msg + headers[src.Kind]
+			last_kind = src.Kind
+		}
+		cp := "   cp: **" + strconv.Itoa(src.MinCP) + "-" + strconv.Itoa(src.MaxCP) + "**"
+
+		switch src.Kind {
+		case "egg":
+			egg := src.Detail
+			if src.Role != "" {
+				egg = egg + " " + src.Role
+			}
+			msg = msg + "  **" + src.Name + "** - " + egg + " egg" + cp
+		case "raid":
+			msg = msg + "  **" + src.Name + "** - " + src.Detail + " raid" + cp
+		case "research":
+			msg = msg + "  **" + src.Name + "** - " + src.Detail + cp
+		case "event":
+			msg = msg + "  **" + src.Name + "** - " + eventRoleLabel(src.Role) + ": " + src.Detail + "   Starts: " + discordTime(src.Start)
+		}
+		msg = msg + "   Shiny: " + convertBool(src.Shiny) + "\n"
+	}
+	if len(sources) == 0 {
+		msg = msg + "No current sources found."
+	}
+	return msg
+}
+
+// func to build a warning line for text replies, empty if the data is fresh or nothing refreshes it
+func staleNotice(data *Refresher) string {
+	if data == nil {
+		return ""
+	}
+	status := data.Status()
+	if !status.Stale {
+		return ""
+	}
+	if status.LastSuccess.IsZero() {
+		return "\n*Data could not be refreshed and may be out of date.*"
+	}
+	return "\n*Data is stale since <t:" + strconv.FormatInt(status.LastSuccess.Unix(), 10) + ":R>, the latest refresh failed.*"
+}
+
+// func to format a stored event time as a discord timestamp, eg. "Sep 11, 2024 6:00 PM (in 2 hours)"
+func discordTime(value string) string {
+	t, err := parseStoredTime(value)
+	if err != nil {
+		return "unknown"
+	}
+	unix := strconv.FormatInt(t.Unix(), 10)
+	return "<t:" + unix + ":f> (<t:" + unix + ":R>)"
+}
@@ -0,0 +1,192 @@
+// embeds.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// colors of the command embeds
+const (
+	colorRaids = 0xE74C3C
+	colorEggs  = 0xF2C94C
+	colorHundo = 0x9B59B6
+	colorBest  = 0x2ECC71
+)
+
+// func to build one embed per raid boss
+func raidEmbeds(raids []Raid) []*discordgo.MessageEmbed {
+	var embeds []*discordgo.MessageEmbed
+	for _, r := range raids {
+		var types []string
+		for _, t := range r.Types {
+			types = append(types, titleCase(t.Name))
+		}
+		var weather []string
+		for _, w := range r.BoostedWeather {
+			weather = append(weather, titleCase(w.Name))
+		}
+
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Title:     shinyMark(r.CanBeShiny) + r.Name,
+			Color:     colorRaids,
+			Thumbnail: thumbnail(r.Image),
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "Tier", Value: r.Tier, Inline: true},
+				{Name: "Types", Value: orNone(strings.Join(types, " / ")), Inline: true},
+				{Name: "Shiny", Value: convertBool(r.CanBeShiny), Inline: true},
+				{Name: "CP", Value: cpRange(r.CombatPower.Normal.Min, r.CombatPower.Normal.Max), Inline: true},
+				{Name: "Weather boosted CP", Value: cpRange(r.CombatPower.Boosted.Min, r.CombatPower.Boosted.Max), Inline: true},
+				{Name: "Boosted in", Value: orNone(strings.Join(weather, ", ")), Inline: true},
+			},
+		})
+	}
+	return embeds
+}
+
+// func to build one embed per pokemon hatching from eggs
+func eggEmbeds(eggs []Egg) []*discordgo.MessageEmbed {
+	var embeds []*discordgo.MessageEmbed
+	for _, e := range eggs {
+		fields := []*discordgo.MessageEmbedField{
+			{Name: "Egg", Value: eggLabel(e), Inline: true},
+			{Name: "CP", Value: cpRange(e.CombatPower.Min, e.CombatPower.Max), Inline: true},
+			{Name: "Shiny", Value: convertBool(e.CanBeShiny), Inline: true},
+		}
+		if e.IsRegional {
+			fields = append(fields, &discordgo.MessageEmbedField{Name: "Regional", Value: "yes", Inline: true})
+		}
+
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Title:     shinyMark(e.CanBeShiny) + e.Name,
+			Color:     colorEggs,
+			Thumbnail: thumbnail(e.Image),
+			Fields:    fields,
+		})
+	}
+	return embeds
+}
+
+// func to build the embed with the 100% iv cp of a pokemon at each level
+func hundoEmbed(result *HundoResult) *discordgo.MessageEmbed {
+	var fields []*discordgo.MessageEmbedField
+	for _, level := range result.Levels {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   level.Label,
+			Value:  "**" + strconv.Itoa(level.CP) + "**",
+			Inline: true,
+		})
+	}
+
+	p := result.Pokemon
+	return &discordgo.MessageEmbed{
+		Title: p.Name,
+		Description: "Base stats: " + strconv.Itoa(p.Attack) + " Atk / " + strconv.Itoa(p.Defense) + " Def / " +
+			strconv.Itoa(p.HP) + " HP",
+		Color:     colorHundo,
+		Thumbnail: thumbnail(result.Image),
+		Fields:    fields,
+		Footer:    &discordgo.MessageEmbedFooter{Text: "CP with 15/15/15 IVs"},
+	}
+}
+
+// func to build the embed ranking the best attackers, the title says what was searched
+func bestEmbed(result *BestResult) *discordgo.MessageEmbed {
+	description := ""
+	if len(result.Attackers) == 0 {
+		// a known pokemon or type that has no attackers worth listing
+		description = "No attacker data for **" + result.Name + "**."
+		if result.Search != "name" {
+			description = "No **" + result.Name + "** attackers found."
+		}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for count, a := range result.Attackers {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name: "#" + strconv.Itoa(count+1) + " " + a.Name,
+			Value: "**" + a.FastMove + "** (" + titleCase(a.FastType) + ") / **" + a.ChargedMove + "** (" + titleCase(a.ChargedType) + ")\n" +
+				"DPS: **" + a.DPS + "**  |  TDO: **" + a.TDO + "**  |  Rating: " + a.ER + "  |  CP: " + a.CP,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       result.Title(),
+		Description: description,
+		Color:       colorBest,
+		Thumbnail:   thumbnail(result.Image),
+		Fields:      fields,
+	}
+}
+
+// footers addFreshness sets, the stale one is the longest
+const (
+	freshFooter   = "Data refreshed"
+	staleFooter   = "Data is stale, the latest refresh failed. Last refreshed"
+	unknownFooter = "Data freshness unknown"
+	footerJoin    = " · "
+)
+
+// characters embedPages keeps free on each page for the footer addFreshness adds after the pages are split
+const freshnessRoom = len(staleFooter) + len(footerJoin)
+
+// func to put the data freshness in the footer of the last embed of a page
+// a footer the embed already has is kept in front, eg. "CP with 15/15/15 IVs · Data refreshed"
+func addFreshness(data *Refresher, embeds []*discordgo.MessageEmbed) {
+	if len(embeds) == 0 || data == nil {
+		return
+	}
+	last := embeds[len(embeds)-1]
+	status := data.Status()
+
+	text := freshFooter
+	if status.Stale {
+		text = staleFooter
+	}
+	if status.LastSuccess.IsZero() {
+		text = unknownFooter
+	} else {
+		last.Timestamp = status.LastSuccess.Format(time.RFC3339)
+	}
+	if last.Footer != nil && last.Footer.Text != "" {
+		text = last.Footer.Text + footerJoin + text
+	}
+	last.Footer = &discordgo.MessageEmbedFooter{Text: text}
+}
+
+// func to build a thumbnail, nil if there is no image
+func thumbnail(url string) *discordgo.MessageEmbedThumbnail {
+	if url == "" {
+		return nil
+	}
+	return &discordgo.MessageEmbedThumbnail{URL: url}
+}
+
+// func to format a cp range, eg. "2260 - 2351"
+func cpRange(low int, high int) string {
+	return strconv.Itoa(low) + " - " + strconv.Itoa(high)
+}
+
+// func to show a dash for empty field values, discord rejects empty fields
+func orNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// func to capitalize each word, eg. "water" gives "Water"
+func titleCase(input string) string {
+	words := strings.Fields(input)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,101 @@
+// errors.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// error for a pokemon, type or other name the user asked for that does not exist
+type NotFoundError struct {
+	Kind       string // what was searched for, eg. "Pokémon" or "type"
+	Name       string // the name as the user typed it
+	Suggestion string // closest known name, empty if nothing is close
+}
+
+func (e *NotFoundError) Error() string {
+	return "no " + e.Kind + " named " + strconv.Quote(e.Name)
+}
+
+// error for data that is not loaded, eg. before the first refresh or when a table is empty
+type DataUnavailableError struct {
+	What string // the data that is missing, eg. "egg"
+	Err  error  // underlying cause, can be nil
+}
+
+func (e *DataUnavailableError) Error() string {
+	if e.Err == nil {
+		return e.What + " data is unavailable"
+	}
+	return e.What + " data is unavailable: " + e.Err.Error()
+}
+
+func (e *DataUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// error for a command that did not finish in time, eg. while the database is busy with a refresh
+type TimeoutError struct {
+	Command string
+	After   time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return e.Command + " timed out after " + e.After.String()
+}
+
+// error for anything else that went wrong, eg. a failed query
+type InternalError struct {
+	Op  string // what was being done, eg. "loading eggs"
+	Err error
+}
+
+func (e *InternalError) Error() string {
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *InternalError) Unwrap() error {
+	return e.Err
+}
+
+// func to build the reply shown to the user for an error, the details stay in the log
+func errorReply(err error) string {
+	var notFound *NotFoundError
+	var unavailable *DataUnavailableError
+	var timeout *TimeoutError
+	switch {
+	case errors.As(err, &notFound):
+		msg := "No " + notFound.Kind + " named '" + notFound.Name + "'"
+		if notFound.Suggestion != "" {
+			return msg + " — did you mean " + notFound.Suggestion + "?"
+		}
+		return msg + "."
+	case errors.As(err, &unavailable):
+		return "The " + unavailable.What + " data isn't available right now, try again in a few minutes."
+	case errors.As(err, &timeout):
+		return "That took too long to look up, try again in a minute."
+	}
+	return "Something went wrong while handling the command, try again later."
+}
+
+// func to log an error and reply with a message only the user can see
+func respondError(r *reply, err error) {
+	logCommandError(slog.With("command", interactionName(r.i), "guild", r.i.GuildID), err)
+	r.fail(errorReply(err))
+}
+
+// func to log an error of a command, names the user typed wrong are not errors of the bot
+func logCommandError(logger *slog.Logger, err error) {
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		logger.Info("Command input not found", "err", err)
+	} else {
+		logger.Error("Error handling command", "err", err)
+	}
+}
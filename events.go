@@ -0,0 +1,223 @@
+// events.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// layout of the event times in events.json, times without a trailing Z are local time
+const eventTimeLayout = "2006-01-02T15:04:05.000"
+
+// roles of the pokemon saved in the event_pokemon table
+const (
+	roleSpawn     = "spawn"     // community day spawn
+	roleSpotlight = "spotlight" // spotlight hour pokemon
+	roleRaidBoss  = "raid"      // raid battle boss
+	roleShiny     = "shiny"     // shiny released with the event
+)
+
+// struct to map the pokemon listed in the event extra data
+type EventPokemon struct {
+	Name       string `json:"name"`
+	Image      string `json:"image"`
+	CanBeShiny bool   `json:"canBeShiny"`
+}
+
+// struct to map the bonuses listed in the event extra data
+type EventBonus struct {
+	Text  string `json:"text"`
+	Image string `json:"image"`
+}
+
+// struct to map the community day extra data
+type CommunityDayData struct {
+	Spawns           []EventPokemon `json:"spawns"`
+	Bonuses          []EventBonus   `json:"bonuses"`
+	BonusDisclaimers []string       `json:"bonusDisclaimers"`
+	Shinies          []EventPokemon `json:"shinies"`
+}
+
+// struct to map the spotlight hour extra data
+type SpotlightData struct {
+	Name       string         `json:"name"`
+	Image      string         `json:"image"`
+	CanBeShiny bool           `json:"canBeShiny"`
+	Bonus      string         `json:"bonus"`
+	List       []EventPokemon `json:"list"`
+}
+
+// struct to map the raid battles extra data
+type RaidBattlesData struct {
+	Bosses  []EventPokemon `json:"bosses"`
+	Shinies []EventPokemon `json:"shinies"`
+}
+
+// struct to map the extra data of an event, only the blocks matching the event type are set
+type EventExtraData struct {
+	Generic struct {
+		HasSpawns             bool `json:"hasSpawns"`
+		HasFieldResearchTasks bool `json:"hasFieldResearchTasks"`
+	} `json:"generic"`
+	CommunityDay *CommunityDayData `json:"communityday,omitempty"`
+	Spotlight    *SpotlightData    `json:"spotlight,omitempty"`
+	RaidBattles  *RaidBattlesData  `json:"raidbattles,omitempty"`
+}
+
+// func to parse an event time into its local wall clock time
+// the result is tagged UTC so the mysql driver stores the wall clock unchanged
+func parseEventTime(value string) sql.NullTime {
+	if value == "" {
+		return sql.NullTime{}
+	}
+
+	// times ending in Z are the same moment everywhere, convert them to local time
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(eventTimeLayout+"Z", value)
+		if err != nil {
+			return sql.NullTime{}
+		}
+		value = t.In(time.Local).Format(eventTimeLayout)
+	}
+
+	t, err := time.ParseInLocation(eventTimeLayout, value, time.UTC)
+	if err != nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// func to insert the events.json data with the schedule, featured pokemon and bonuses of each event
+func insertEvents(tx *sql.Tx, events []Event) error {
+	for _, e := range events {
+		_, err := tx.Exec(`INSERT INTO events (event_id, name, event_type, heading, link, image, start_time, end_time)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			e.EventID, e.Name, e.EventType, e.Heading, e.Link, e.Image, parseEventTime(e.Start), parseEventTime(e.End))
+		if err != nil {
+			return fmt.Errorf("error inserting event %s: %w", e.EventID, err)
+		}
+
+		err = insertEventPokemon(tx, e)
+		if err != nil {
+			return fmt.Errorf("error inserting pokemon of event %s: %w", e.EventID, err)
+		}
+		err = insertEventBonuses(tx, e)
+		if err != nil {
+			return fmt.Errorf("error inserting bonuses of event %s: %w", e.EventID, err)
+		}
+	}
+	return nil
+}
+
+// func to insert the spawns, spotlight pokemon, raid bosses and shinies of an event
+func insertEventPokemon(tx *sql.Tx, e Event) error {
+	insert := func(role string, list []EventPokemon, shiny bool) error {
+		for _, p := range list {
+			_, err := tx.Exec("INSERT INTO event_pokemon (event_id, role, name, image, shiny) VALUES (?, ?, ?, ?, ?)",
+				e.EventID, role, p.Name, p.Image, shiny || p.CanBeShiny)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if cd := e.ExtraData.CommunityDay; cd != nil {
+		err := insert(roleSpawn, cd.Spawns, false)
+		if err != nil {
+			return err
+		}
+		err = insert(roleShiny, cd.Shinies, true)
+		if err != nil {
+			return err
+		}
+	}
+	if sp := e.ExtraData.Spotlight; sp != nil {
+		// the list holds every featured pokemon, older entries only have the single name
+		list := sp.List
+		if len(list) == 0 && sp.Name != "" {
+			list = []EventPokemon{{Name: sp.Name, Image: sp.Image, CanBeShiny: sp.CanBeShiny}}
+		}
+		err := insert(roleSpotlight, list, false)
+		if err != nil {
+			return err
+		}
+	}
+	if rb := e.ExtraData.RaidBattles; rb != nil {
+		err := insert(roleRaidBoss, rb.Bosses, false)
+		if err != nil {
+			return err
+		}
+		err = insert(roleShiny, rb.Shinies, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// func to insert the community day and spotlight hour bonuses of an event
+func insertEventBonuses(tx *sql.Tx, e Event) error {
+	insert := func(text string, image string, disclaimer bool) error {
+		_, err := tx.Exec("INSERT INTO event_bonuses (event_id, text, image, disclaimer) VALUES (?, ?, ?, ?)",
+			e.EventID, text, image, disclaimer)
+		return err
+	}
+
+	if cd := e.ExtraData.CommunityDay; cd != nil {
+		for _, b := range cd.Bonuses {
+			err := insert(b.Text, b.Image, false)
+			if err != nil {
+				return err
+			}
+		}
+		for _, d := range cd.BonusDisclaimers {
+			err := insert(d, "", true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if sp := e.ExtraData.Spotlight; sp != nil && sp.Bonus != "" {
+		err := insert(sp.Bonus, "", false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// func to get the wall clock of a local time tagged UTC, matching how event times are stored
+func wallClock(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// func to read a stored event time back as a local time
+// mysql returns "2006-01-02 15:04:05", sqlite returns the UTC tagged wall clock in RFC 3339
+func parseStoredTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+}
+
+// func to describe how a pokemon is featured in an event
+func eventRoleLabel(role string) string {
+	switch role {
+	case roleSpawn:
+		return "Community Day spawn"
+	case roleSpotlight:
+		return "Spotlight Hour"
+	case roleRaidBoss:
+		return "Event raid boss"
+	}
+	return "Event"
+}
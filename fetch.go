@@ -0,0 +1,159 @@
+// fetch.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// json files used from the ScrapedDuck data branch
+var dataFiles = []string{"eggs.json", "raids.json", "events.json", "research.json"}
+
+// name of the file holding the cache headers of each download
+const fetchCacheFile = ".fetch-cache.json"
+
+// struct to map the cache headers of a downloaded file
+type fileCache struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"lastModified"`
+}
+
+// struct to download the ScrapedDuck json files over http
+type HTTPFetcher struct {
+	BaseURL string       // url the file names are appended to
+	Dir     string       // folder the files are written into
+	Files   []string     // file names to download
+	Client  *http.Client // client used for every request
+}
+
+// func to create a fetcher for the default data files
+func NewHTTPFetcher(baseURL, dir string) *HTTPFetcher {
+	return &HTTPFetcher{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Dir:     dir,
+		Files:   dataFiles,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// func to download every file, returns true if any file changed
+func (f *HTTPFetcher) Fetch() (bool, error) {
+	err := os.MkdirAll(f.Dir, os.ModePerm)
+	if err != nil {
+		return false, fmt.Errorf("failed to create data folder: %w", err)
+	}
+
+	cache := f.loadCache()
+	changed := false
+
+	for _, name := range f.Files {
+		entry := cache[name]
+		updated, err := f.fetchFile(name, &entry)
+		if err != nil {
+			return false, err
+		}
+		if updated {
+			cache[name] = entry
+			changed = true
+		}
+	}
+
+	if changed {
+		err = f.saveCache(cache)
+		if err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// func to download a single file, skipped when the server reports it unchanged
+func (f *HTTPFetcher) fetchFile(name string, entry *fileCache) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, f.BaseURL+"/"+name, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build request for %s: %w", name, err)
+	}
+
+	// only send the cache headers if the file from the last download is still there
+	destPath := filepath.Join(f.Dir, name)
+	if _, err := os.Stat(destPath); err == nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return false, nil
+	case http.StatusOK:
+	default:
+		return false, fmt.Errorf("failed to download %s: unexpected status %s", name, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	// write to a temp file first so readers never see a half written file
+	tmpPath := destPath + ".tmp"
+	err = os.WriteFile(tmpPath, body, 0644)
+	if err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	err = os.Rename(tmpPath, destPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	entry.ETag = resp.Header.Get("ETag")
+	entry.LastModified = resp.Header.Get("Last-Modified")
+	return true, nil
+}
+
+// func to read the cache headers saved by the last fetch
+func (f *HTTPFetcher) loadCache() map[string]fileCache {
+	cache := map[string]fileCache{}
+
+	input, err := os.ReadFile(filepath.Join(f.Dir, fetchCacheFile))
+	if err != nil {
+		return cache
+	}
+	// a broken cache file only means every file gets downloaded again
+	if json.Unmarshal(input, &cache) != nil {
+		return map[string]fileCache{}
+	}
+	return cache
+}
+
+// func to save the cache headers for the next fetch
+func (f *HTTPFetcher) saveCache(cache map[string]fileCache) error {
+	output, err := json.MarshalIndent(cache, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fetch cache: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(f.Dir, fetchCacheFile), output, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write fetch cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// fetch_test.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+const (
+	testETag         = `"v1"`
+	testLastModified = "Sun, 18 Oct 2026 08:00:00 GMT"
+	testBody         = `[{"name":"Kyogre"}]`
+)
+
+// struct for a fake data server, it answers with the status set with respondWith and records the request headers
+type fakeDataServer struct {
+	mu      sync.Mutex
+	next    int           // status of the following responses, 0 answers normally
+	headers []http.Header // headers of every request
+}
+
+// func to set the status of the following responses
+func (f *fakeDataServer) respondWith(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next = status
+}
+
+func (f *fakeDataServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.headers = append(f.headers, r.Header.Clone())
+	status := f.next
+	f.mu.Unlock()
+
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	if r.Header.Get("If-None-Match") == testETag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", testETag)
+	w.Header().Set("Last-Modified", testLastModified)
+	w.Write([]byte(testBody))
+}
+
+// func to create a fetcher for one file from a fake server
+func newTestFetcher(t *testing.T) (*HTTPFetcher, *fakeDataServer) {
+	t.Helper()
+	fake := &fakeDataServer{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	f := NewHTTPFetcher(server.URL, t.TempDir())
+	f.Files = []string{"eggs.json"}
+	return f, fake
+}
+
+// func to read the downloaded file
+func readFetched(t *testing.T, f *HTTPFetcher) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.Dir, "eggs.json"))
+	if err != nil {
+		t.Fatalf("reading fetched file: %v", err)
+	}
+	return string(data)
+}
+
+func TestFetchSendsCacheHeaders(t *testing.T) {
+	f, fake := newTestFetcher(t)
+
+	changed, err := f.Fetch()
+	if err != nil || !changed {
+		t.Fatalf("first fetch = %v, %v, want true, nil", changed, err)
+	}
+	changed, err = f.Fetch()
+	if err != nil {
+		t.Fatalf("second fetch: %v", err)
+	}
+
+	first, second := fake.headers[0], fake.headers[1]
+	if first.Get("If-None-Match") != "" || first.Get("If-Modified-Since") != "" {
+		t.Errorf("first fetch sent cache headers %v", first)
+	}
+	if got := second.Get("If-None-Match"); got != testETag {
+		t.Errorf("If-None-Match = %q, want %q", got, testETag)
+	}
+	if got := second.Get("If-Modified-Since"); got != testLastModified {
+		t.Errorf("If-Modified-Since = %q, want %q", got, testLastModified)
+	}
+	if changed {
+		t.Error("second fetch reported a change for an unchanged file")
+	}
+}
+
+func TestFetchNotModifiedKeepsFile(t *testing.T) {
+	f, fake := newTestFetcher(t)
+
+	_, err := f.Fetch()
+	if err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	fake.respondWith(http.StatusNotModified)
+	changed, err := f.Fetch()
+	if err != nil {
+		t.Fatalf("fetch on 304: %v", err)
+	}
+	if changed {
+		t.Error("fetch on 304 reported a change")
+	}
+	if got := readFetched(t, f); got != testBody {
+		t.Errorf("file after 304 = %q, want %q", got, testBody)
+	}
+}
+
+func TestFetchErrorKeepsPreviousFile(t *testing.T) {
+	f, fake := newTestFetcher(t)
+
+	_, err := f.Fetch()
+	if err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	for _, status := range []int{http.StatusInternalServerError, http.StatusNotFound, http.StatusForbidden} {
+		fake.respondWith(status)
+		changed, err := f.Fetch()
+		if err == nil {
+			t.Errorf("fetch on %d returned no error", status)
+		}
+		if changed {
+			t.Errorf("fetch on %d reported a change", status)
+		}
+		if got := readFetched(t, f); got != testBody {
+			t.Errorf("file after %d = %q, want %q", status, got, testBody)
+		}
+	}
+}
@@ -1,11 +1,12 @@
 // getFiles.go
 // Author: Cade Beckers
 // Written: 08/23/2024
-// Updated: 09/10/2024
+// Updated: 10/18/2026
 
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
 // struct to map eggs to json
@@ -29,27 +32,31 @@ type Egg struct {
 	IsRegional bool `json:"isRegional"`
 }
 
-// struct to map events to json (not in use)
+// struct to map events to json
 type Event struct {
-	EventID   string `json:"eventID"`
-	Name      string `json:"name"`
-	EventType string `json:"eventType"`
-	Heading   string `json:"heading"`
-	Link      string `json:"link"`
-	Image     string `json:"image"`
-	Start     string `json:"start"`
-	End       string `json:"end"`
+	EventID   string         `json:"eventID"`
+	Name      string         `json:"name"`
+	EventType string         `json:"eventType"`
+	Heading   string         `json:"heading"`
+	Link      string         `json:"link"`
+	Image     string         `json:"image"`
+	Start     string         `json:"start"`
+	End       string         `json:"end"`
+	ExtraData EventExtraData `json:"extraData"`
+}
+
+// struct to map raid types and boosted weather to json
+type TypeInfo struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
 }
 
 // struct to map raids to json
 type Raid struct {
-	Name       string `json:"name"`
-	Tier       string `json:"tier"`
-	CanBeShiny bool   `json:"canBeShiny"`
-	Types      []struct {
-		Name  string `json:"name"`
-		Image string `json:"image"`
-	} `json:"types"`
+	Name        string     `json:"name"`
+	Tier        string     `json:"tier"`
+	CanBeShiny  bool       `json:"canBeShiny"`
+	Types       []TypeInfo `json:"types"`
 	CombatPower struct {
 		Normal struct {
 			Min int `json:"min"`
@@ -60,14 +67,11 @@ type Raid struct {
 			Max int `json:"max"`
 		} `json:"boosted"`
 	} `json:"combatPower"`
-	BoostedWeather []struct {
-		Name  string `json:"name"`
-		Image string `json:"image"`
-	} `json:"boostedWeather"`
-	Image string `json:"image"`
+	BoostedWeather []TypeInfo `json:"boostedWeather"`
+	Image          string     `json:"image"`
 }
 
-// struct to map rewards to json (not in use)
+// struct to map rewards to json
 type Reward struct {
 	Name        string `json:"name"`
 	Image       string `json:"image"`
@@ -78,7 +82,7 @@ type Reward struct {
 	} `json:"combatPower"`
 }
 
-// struct to map researches to json (not in use)
+// struct to map researches to json
 type ResearchTask struct {
 	Text    string   `json:"text"`
 	Type    string   `json:"type"`
@@ -141,242 +145,384 @@ func CopyFilesFromBranch(clonePath, branchName, outputPath string) error {
 	return nil
 }
 
-// func to refresh the values in the database
-func refreshDB() {
-	clearTables()
-	readEgg()
-	readEvent()
-	readRaid()
-	readResearches()
+// struct holding one full pull of the ScrapedDuck data
+type Dataset struct {
+	Eggs       []Egg
+	Events     []Event
+	Raids      []Raid
+	Researches []ResearchTask
 }
 
-// func to read the egg.json data
-func readEgg() {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
+// func to read and check every json file before the database is touched
+func loadDataset(src DataSource) (*Dataset, error) {
+	var ds Dataset
 
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
+	err := readJSON(src, "eggs.json", &ds.Eggs)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	defer db.Close()
-
-	// ping the database to verify the connection
-	err = db.Ping()
+	err = readJSON(src, "events.json", &ds.Events)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-
-	// read json file
-	jsonFile, err := os.ReadFile("./data/eggs.json")
+	err = readJSON(src, "raids.json", &ds.Raids)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
-
-	// export data into array
-	var pokemon []Egg
-	err = json.Unmarshal(jsonFile, &pokemon)
+	err = readJSON(src, "research.json", &ds.Researches)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
 
-	// loop each element and generate query
-	for _, p := range pokemon {
-		if strings.Contains(p.Name, "'") {
-			p.Name = strings.ReplaceAll(p.Name, "'", "")
-		}
-
-		query := fmt.Sprintf(`INSERT INTO eggs (name, distance, adventure_sync, image, shiny, min_cp, max_cp, regional) 
-			VALUES ('%s', '%s', %t, '%s', %t, %d, %d, %t);`,
-			p.Name, p.EggType, p.IsAdventureSync, p.Image, p.CanBeShiny, p.CombatPower.Min, p.CombatPower.Max, p.IsRegional)
-		rows, err := db.Query(query)
-		if err != nil {
-			panic(err)
-		}
-		defer rows.Close()
+	// an empty egg or raid pool means the pull went wrong, not that the game has none
+	if len(ds.Eggs) == 0 {
+		return nil, fmt.Errorf("eggs.json has no eggs")
 	}
-}
-
-// func to read events.json data (not in use)
-func readEvent() {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
-
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
+	if len(ds.Raids) == 0 {
+		return nil, fmt.Errorf("raids.json has no raids")
 	}
-	defer db.Close()
+	return &ds, nil
+}
 
-	// ping the database to verify the connection
-	err = db.Ping()
+// func to read a json file from the data source into the given value
+func readJSON(src DataSource, name string, v any) error {
+	jsonFile, err := src.ReadFile(name)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error reading %s: %w", name, err)
 	}
-
-	// read json file
-	jsonFile, err := os.ReadFile("./data/events.json")
+	err = json.Unmarshal(jsonFile, v)
 	if err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+		return fmt.Errorf("error unmarshalling %s: %w", name, err)
 	}
+	return nil
+}
 
-	// export data into array
-	var events []Event
-	err = json.Unmarshal(jsonFile, &events)
+// func to refresh the values in the database from the given data source
+// the store swaps the data in one transaction so the old data stays in place until the new data is complete
+// returns the changes against the previous data, nil if there is no previous data to compare with
+func refreshDB(ctx context.Context, st Store, src DataSource) (*DatasetDiff, error) {
+	// parse every file first so a bad pull never reaches the database
+	ds, err := loadDataset(src)
 	if err != nil {
-		fmt.Println("Error unmarshalling JSON:", err)
-		return
-	}
-
-	// loop each element and generate query
-	for _, e := range events {
-		query := fmt.Sprintf(`INSERT INTO events (event_id, name, event_type, heading, link, image) 
-			VALUES ('%s', '%s', '%s', '%s', '%s', '%s');`,
-			e.EventID, e.Name, e.EventType, e.Heading, e.Link, e.Image)
-		rows, err := db.Query(query)
-		if err != nil {
-			panic(err)
-		}
-		defer rows.Close()
+		return nil, err
 	}
-}
 
-// func to read raids.json data
-func readRaid() {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
-
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
+	// compare with the data currently served
+	var diff *DatasetDiff
+	previous := previousDataset()
+	if previous != nil {
+		diff = diffDatasets(previous, ds)
 	}
-	defer db.Close()
 
-	// ping the database to verify the connection
-	err = db.Ping()
+	err = st.ReplaceDataset(ctx, ds, diff)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	// read json file
-	jsonFile, err := os.ReadFile("./data/raids.json")
-	if err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+	// only swap the served data once the database has it
+	datasetMu.Lock()
+	currentDataset = ds
+	if diff != nil && !diff.Empty() {
+		latestDiff = diff
 	}
+	datasetMu.Unlock()
+	return diff, nil
+}
 
-	// export data into array
-	var raids []Raid
-	err = json.Unmarshal(jsonFile, &raids)
-	if err != nil {
-		fmt.Println("Error unmarshalling JSON:", err)
-		return
+// func to insert the eggs.json data
+func insertEggs(tx *sql.Tx, pokemon []Egg) error {
+	// loop each element and generate query
+	for _, p := range pokemon {
+		_, err := tx.Exec(`INSERT INTO eggs (name, distance, adventure_sync, image, shiny, min_cp, max_cp, regional)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?);`,
+			p.Name, p.EggType, p.IsAdventureSync, p.Image, p.CanBeShiny, p.CombatPower.Min, p.CombatPower.Max, p.IsRegional)
+		if err != nil {
+			return fmt.Errorf("error inserting egg %s: %w", p.Name, err)
+		}
 	}
+	return nil
+}
 
+// func to insert the raids.json data
+func insertRaids(tx *sql.Tx, raids []Raid) error {
 	// loop each element and generate query
 	for _, raid := range raids {
 		// get each raid type
-		var typesStr string
+		var types []string
 		for _, t := range raid.Types {
-			typesStr += t.Name + ","
+			types = append(types, t.Name)
 		}
-		typesStr = typesStr[:len(typesStr)-1] // remove trailing comma
+		typesStr := strings.Join(types, ",")
 
 		// each weather condition
-		var boostedWeatherStr string
+		var boostedWeather []string
 		for _, bw := range raid.BoostedWeather {
-			boostedWeatherStr += bw.Name + ","
+			boostedWeather = append(boostedWeather, bw.Name)
 		}
-		boostedWeatherStr = boostedWeatherStr[:len(boostedWeatherStr)-1] // remove trailing comma
+		boostedWeatherStr := strings.Join(boostedWeather, ",")
 
-		// build query and execute
-		query := fmt.Sprintf(`INSERT INTO raids (name, tier, shiny, types, min_cp, max_cp, wb_min_cp, wb_max_cp, boosted_weather, image) 
-			VALUES ('%s', '%s', %t, '%s', %d, %d, %d, %d, '%s', '%s');`,
+		// insert with placeholders so names with quotes are stored as they are
+		_, err := tx.Exec(`INSERT INTO raids (name, tier, shiny, types, min_cp, max_cp, wb_min_cp, wb_max_cp, boosted_weather, image)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
 			raid.Name, raid.Tier, raid.CanBeShiny, typesStr, raid.CombatPower.Normal.Min, raid.CombatPower.Normal.Max, raid.CombatPower.Boosted.Min, raid.CombatPower.Boosted.Max, boostedWeatherStr, raid.Image)
+		if err != nil {
+			return fmt.Errorf("error inserting raid %s: %w", raid.Name, err)
+		}
+	}
+	return nil
+}
+
+// func to insert the research.json data
+func insertResearches(tx *sql.Tx, tasks []ResearchTask) error {
+	// loop each element and generate query
+	for _, task := range tasks {
+		for _, reward := range task.Rewards {
+			// combine task with reward data
+			_, err := tx.Exec(`INSERT INTO researches (text, type, reward, shiny, min_cp, max_cp, image)
+				VALUES (?, ?, ?, ?, ?, ?, ?);`,
+				task.Text, task.Type, reward.Name, reward.CanBeShiny, reward.CombatPower.Min, reward.CombatPower.Max, reward.Image)
+			if err != nil {
+				return fmt.Errorf("error inserting research %s: %w", task.Text, err)
+			}
+		}
+	}
+	return nil
+}
 
-		rows, err := db.Query(query)
+// func to clear tables inside the refresh transaction
+func clearTables(tx *sql.Tx) error {
+	commands := [6]string{"DELETE FROM eggs", "DELETE FROM events", "DELETE FROM event_pokemon", "DELETE FROM event_bonuses", "DELETE FROM raids", "DELETE FROM researches"}
+	for i := 0; i < len(commands); i++ {
+		_, err := tx.Exec(commands[i])
 		if err != nil {
-			panic(err)
+			return err
 		}
-		defer rows.Close()
 	}
+	return nil
 }
 
-// func to read researches.json data (not in use)
-func readResearches() {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
+// data currently served, used to find what changed on the next refresh
+var (
+	datasetMu      sync.RWMutex
+	currentDataset *Dataset     // last dataset written to the database
+	latestDiff     *DatasetDiff // changes found by the last refresh that changed anything
+)
 
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
+// struct pairing a research task with one of its rewards
+type ResearchReward struct {
+	Task   string `json:"task"`
+	Type   string `json:"type"`
+	Reward Reward `json:"reward"`
+}
+
+// struct for a pokemon that can now be shiny and where it can be found
+type ShinyDebut struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Image  string `json:"image"`
+}
+
+// struct holding everything that changed between two datasets
+type DatasetDiff struct {
+	Time            time.Time        `json:"time"`
+	AddedRaids      []Raid           `json:"addedRaids"`
+	RemovedRaids    []Raid           `json:"removedRaids"`
+	AddedEggs       []Egg            `json:"addedEggs"`
+	RemovedEggs     []Egg            `json:"removedEggs"`
+	AddedResearch   []ResearchReward `json:"addedResearch"`
+	RemovedResearch []ResearchReward `json:"removedResearch"`
+	NewShinies      []ShinyDebut     `json:"newShinies"`
+	NewEvents       []Event          `json:"newEvents"`
+	EndedEvents     []Event          `json:"endedEvents"`
+}
+
+// func to check if nothing changed
+func (d *DatasetDiff) Empty() bool {
+	return len(d.AddedRaids) == 0 && len(d.RemovedRaids) == 0 &&
+		len(d.AddedEggs) == 0 && len(d.RemovedEggs) == 0 &&
+		len(d.AddedResearch) == 0 && len(d.RemovedResearch) == 0 &&
+		len(d.NewShinies) == 0 && len(d.NewEvents) == 0 && len(d.EndedEvents) == 0
+}
+
+// func to check if the raids, eggs or research changed, the changes that get announced
+func (d *DatasetDiff) RotationChanged() bool {
+	return len(d.AddedRaids) > 0 || len(d.RemovedRaids) > 0 ||
+		len(d.AddedEggs) > 0 || len(d.RemovedEggs) > 0 ||
+		len(d.AddedResearch) > 0 || len(d.RemovedResearch) > 0
+}
+
+// func to get the changes found by the last refresh, falls back to the database after a restart
+// nil if nothing was recorded yet
+func LatestChanges(ctx context.Context, db Store) (*DatasetDiff, error) {
+	datasetMu.RLock()
+	diff := latestDiff
+	datasetMu.RUnlock()
+	if diff != nil {
+		return diff, nil
 	}
-	defer db.Close()
+	return db.LatestChanges(ctx)
+}
 
-	// ping the database to verify the connection
-	err = db.Ping()
-	if err != nil {
-		panic(err)
+// func to get the data to compare a new refresh against
+func previousDataset() *Dataset {
+	datasetMu.RLock()
+	ds := currentDataset
+	datasetMu.RUnlock()
+	if ds != nil {
+		return ds
 	}
 
-	// read json file
-	jsonFile, err := os.ReadFile("./data/research.json")
+	// first refresh since start, the snapshot holds the last imported data
+	ds, err := loadDataset(&DirSource{Path: snapshotPath})
 	if err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+		return nil
 	}
+	return ds
+}
 
-	// export data into array
-	var tasks []ResearchTask
-	err = json.Unmarshal(jsonFile, &tasks)
+// func to save a diff inside the refresh transaction
+func insertDiff(tx *sql.Tx, diff *DatasetDiff) error {
+	changes, err := json.Marshal(diff)
 	if err != nil {
-		fmt.Println("Error unmarshalling JSON:", err)
-		return
+		return err
 	}
+	_, err = tx.Exec("INSERT INTO dataset_changes (created_at, changes) VALUES (?, ?)", diff.Time, string(changes))
+	if err != nil {
+		return fmt.Errorf("error inserting dataset changes: %w", err)
+	}
+	return nil
+}
 
-	// loop each element and generate query
-	for _, task := range tasks {
-		for _, reward := range task.Rewards {
-			// combine task with reward data
-			query := fmt.Sprintf(`INSERT INTO researches (text, type, reward, shiny, min_cp, max_cp, image) 
-				VALUES ('%s', '%s', '%s', %t, %d, %d, '%s');`,
-				task.Text, task.Type, reward.Name, reward.CanBeShiny, reward.CombatPower.Min, reward.CombatPower.Max, reward.Image)
-			rows, err := db.Query(query)
-			if err != nil {
-				panic(err)
-			}
-			defer rows.Close()
+// func to compare two datasets
+func diffDatasets(old *Dataset, latest *Dataset) *DatasetDiff {
+	diff := &DatasetDiff{Time: time.Now()}
+
+	// raids are keyed by name and tier
+	raidKey := func(r Raid) string { return r.Name + "|" + r.Tier }
+	oldRaids := map[string]bool{}
+	for _, r := range old.Raids {
+		oldRaids[raidKey(r)] = true
+	}
+	newRaids := map[string]bool{}
+	for _, r := range latest.Raids {
+		newRaids[raidKey(r)] = true
+		if !oldRaids[raidKey(r)] {
+			diff.AddedRaids = append(diff.AddedRaids, r)
+		}
+	}
+	for _, r := range old.Raids {
+		if !newRaids[raidKey(r)] {
+			diff.RemovedRaids = append(diff.RemovedRaids, r)
 		}
 	}
-}
 
-// func to clear tables
-func clearTables() {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
+	// eggs are keyed by name, distance and adventure sync
+	eggKey := func(e Egg) string { return fmt.Sprintf("%s|%s|%t", e.Name, e.EggType, e.IsAdventureSync) }
+	oldEggs := map[string]bool{}
+	for _, e := range old.Eggs {
+		oldEggs[eggKey(e)] = true
+	}
+	newEggs := map[string]bool{}
+	for _, e := range latest.Eggs {
+		newEggs[eggKey(e)] = true
+		if !oldEggs[eggKey(e)] {
+			diff.AddedEggs = append(diff.AddedEggs, e)
+		}
+	}
+	for _, e := range old.Eggs {
+		if !newEggs[eggKey(e)] {
+			diff.RemovedEggs = append(diff.RemovedEggs, e)
+		}
+	}
 
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
+	// research is keyed by task text and reward name
+	researchKey := func(r ResearchReward) string { return r.Task + "|" + r.Reward.Name }
+	oldResearch := map[string]bool{}
+	for _, r := range flattenResearch(old.Researches) {
+		oldResearch[researchKey(r)] = true
+	}
+	newResearch := map[string]bool{}
+	for _, r := range flattenResearch(latest.Researches) {
+		newResearch[researchKey(r)] = true
+		if !oldResearch[researchKey(r)] {
+			diff.AddedResearch = append(diff.AddedResearch, r)
+		}
+	}
+	for _, r := range flattenResearch(old.Researches) {
+		if !newResearch[researchKey(r)] {
+			diff.RemovedResearch = append(diff.RemovedResearch, r)
+		}
 	}
-	defer db.Close()
 
-	// ping the database to verify the connection
-	err = db.Ping()
-	if err != nil {
-		panic(err)
+	// events are keyed by id
+	oldEvents := map[string]bool{}
+	for _, e := range old.Events {
+		oldEvents[e.EventID] = true
+	}
+	newEvents := map[string]bool{}
+	for _, e := range latest.Events {
+		newEvents[e.EventID] = true
+		if !oldEvents[e.EventID] {
+			diff.NewEvents = append(diff.NewEvents, e)
+		}
+	}
+	for _, e := range old.Events {
+		if !newEvents[e.EventID] {
+			diff.EndedEvents = append(diff.EndedEvents, e)
+		}
 	}
 
-	// clear tables
-	commands := [4]string{"DELETE FROM eggs", "DELETE FROM events", "DELETE FROM raids", "DELETE FROM researches"}
-	for i := 0; i < len(commands); i++ {
-		rows, err := db.Query(commands[i])
-		if err != nil {
-			panic(err)
+	// a shiny debut is a pokemon that could not be shiny anywhere before
+	oldShinies := shinyNames(old)
+	seen := map[string]bool{}
+	for _, debut := range shinySources(latest) {
+		if oldShinies[debut.Name] || seen[debut.Name] {
+			continue
 		}
-		defer rows.Close()
+		seen[debut.Name] = true
+		diff.NewShinies = append(diff.NewShinies, debut)
+	}
+
+	return diff
+}
+
+// func to split research tasks into one entry per reward
+func flattenResearch(tasks []ResearchTask) []ResearchReward {
+	var rewards []ResearchReward
+	for _, task := range tasks {
+		for _, reward := range task.Rewards {
+			rewards = append(rewards, ResearchReward{Task: task.Text, Type: task.Type, Reward: reward})
+		}
+	}
+	return rewards
+}
+
+// func to list every shiny eligible pokemon in a dataset with where it is found
+func shinySources(ds *Dataset) []ShinyDebut {
+	var shinies []ShinyDebut
+	for _, r := range ds.Raids {
+		if r.CanBeShiny {
+			shinies = append(shinies, ShinyDebut{Name: r.Name, Source: r.Tier + " raids", Image: r.Image})
+		}
+	}
+	for _, e := range ds.Eggs {
+		if e.CanBeShiny {
+			shinies = append(shinies, ShinyDebut{Name: e.Name, Source: e.EggType + " eggs", Image: e.Image})
+		}
+	}
+	for _, r := range flattenResearch(ds.Researches) {
+		if r.Reward.CanBeShiny {
+			shinies = append(shinies, ShinyDebut{Name: r.Reward.Name, Source: "Research: " + r.Task, Image: r.Reward.Image})
+		}
+	}
+	return shinies
+}
+
+// func to get the names of every shiny eligible pokemon in a dataset
+func shinyNames(ds *Dataset) map[string]bool {
+	names := map[string]bool{}
+	for _, s := range shinySources(ds) {
+		names[s.Name] = true
 	}
+	return names
 }
@@ -0,0 +1,683 @@
+// handlers.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// every command of the bot, adding a command means writing its type and listing it here
+var botCommands = []Command{
+	bestCommand{},
+	eventsCommand{},
+	hundoCommand{},
+	eggsCommand{},
+	raidsCommand{},
+	announceCommand{},
+	researchCommand{},
+	refreshCommand{},
+	changesCommand{},
+	whereCommand{},
+	xpCommand{},
+}
+
+// permission needed for the admin commands
+var manageServer int64 = discordgo.PermissionManageServer
+
+// the permission above is not checked in dms, so the admin commands are left out of them
+var noDMs = false
+
+// command ranking the best attackers of a type or the best movesets of a pokemon
+type bestCommand struct{}
+
+func (bestCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "best",
+		Description: "Gives you the best pokemon for specified category.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "search_setting",
+				Description: "Search method",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Same Type Moves",
+						Value: "sametype",
+					},
+					{
+						Name:  "Mixed Type Moves",
+						Value: "mixtype",
+					},
+					{
+						Name:  "Name",
+						Value: "name",
+					},
+				},
+			},
+			{
+				Name:        "sort_by",
+				Description: "Sorting method",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Damage per Second",
+						Value: "dps",
+					},
+					{
+						Name:  "Total Damage Output",
+						Value: "tdo",
+					},
+					{
+						Name:  "Effectiveness Rating",
+						Value: "er",
+					},
+				},
+			},
+			{
+				Name:        "number_of_results",
+				Description: "Top # of results needed",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Top 10",
+						Value: "10",
+					},
+					{
+						Name:  "Top 5",
+						Value: "5",
+					},
+					{
+						Name:  "Top 3",
+						Value: "3",
+					},
+					{
+						Name:  "Top 1",
+						Value: "1",
+					},
+				},
+			},
+			{
+				Name:         "name_or_type",
+				Description:  "Enter pokemon name",
+				Type:         discordgo.ApplicationCommandOptionString,
+				Required:     true,
+				Autocomplete: true,
+			},
+		},
+	}
+}
+
+func (bestCommand) Candidates(ctx *Context, option string) ([]string, error) {
+	if option != "name_or_type" {
+		return nil, nil
+	}
+	// type searches get types, name searches get pokemon
+	switch ctx.String("search_setting") {
+	case "sametype", "mixtype":
+		return pokemonTypes, nil
+	}
+	return pokemonCandidates(ctx)
+}
+
+// func to get the pokemon names to suggest, unavailable until the base stats are loaded
+func pokemonCandidates(ctx *Context) ([]string, error) {
+	names, err := ctx.Store.PokemonNames(ctx.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, &DataUnavailableError{What: "Pokémon stats"}
+	}
+	return names, nil
+}
+
+func (bestCommand) Handle(ctx *Context) error {
+	// build response
+	result, err := getBest(ctx.Ctx, ctx.Store, ctx.String("search_setting"), ctx.String("sort_by"), ctx.String("number_of_results"), ctx.String("name_or_type"))
+	if err != nil {
+		return err
+	}
+
+	// push message
+	ctx.Out.Best(result)
+	return nil
+}
+
+// command listing the live or upcoming events
+type eventsCommand struct{}
+
+func (eventsCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "events",
+		Description: "Gives you the live or upcoming events.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "when",
+				Description: "Live events or events starting soon",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Live now",
+						Value: "live",
+					},
+					{
+						Name:  "Next 7 days",
+						Value: "week",
+					},
+				},
+			},
+			{
+				Name:        "type",
+				Description: "Only show one type of event",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Community Day",
+						Value: "community-day",
+					},
+					{
+						Name:  "Raid Hour",
+						Value: "raid-hour",
+					},
+					{
+						Name:  "Raid Day",
+						Value: "raid-day",
+					},
+					{
+						Name:  "Raid Battles",
+						Value: "raid-battles",
+					},
+					{
+						Name:  "Pokemon Spotlight Hour",
+						Value: "pokemon-spotlight-hour",
+					},
+					{
+						Name:  "GO Battle League",
+						Value: "go-battle-league",
+					},
+					{
+						Name:  "Ticketed Event",
+						Value: "ticketed-event",
+					},
+					{
+						Name:  "Event",
+						Value: "event",
+					},
+					{
+						Name:  "Research",
+						Value: "research",
+					},
+					{
+						Name:  "PokeStop Showcase",
+						Value: "pokestop-showcase",
+					},
+					{
+						Name:  "Safari Zone",
+						Value: "safari-zone",
+					},
+					{
+						Name:  "City Safari",
+						Value: "city-safari",
+					},
+					{
+						Name:  "Wild Area",
+						Value: "wild-area",
+					},
+					{
+						Name:  "Season",
+						Value: "season",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (eventsCommand) Handle(ctx *Context) error {
+	// build response, the type is optional and empty when not set
+	when := ctx.String("when")
+	events, err := getEvents(ctx.Ctx, ctx.Store, ctx.Data, when, ctx.String("type"))
+	if err != nil {
+		return err
+	}
+
+	// push message
+	ctx.Out.Events(when, events)
+	return nil
+}
+
+// command showing the cp of a pokemon with perfect ivs
+type hundoCommand struct{}
+
+func (hundoCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "hundo",
+		Description: "Gives you the hundo numbers for a specific pokemon.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:         "pokemon",
+				Description:  "Pokemon it search for. Examples: mewtwo | charmander | kartana",
+				Type:         discordgo.ApplicationCommandOptionString,
+				Required:     true,
+				Autocomplete: true,
+			},
+		},
+	}
+}
+
+func (hundoCommand) Candidates(ctx *Context, option string) ([]string, error) {
+	if option != "pokemon" {
+		return nil, nil
+	}
+	return pokemonCandidates(ctx)
+}
+
+func (hundoCommand) Handle(ctx *Context) error {
+	// build response
+	result, err := getHundo(ctx.Ctx, ctx.Store, ctx.String("pokemon"))
+	if err != nil {
+		return err
+	}
+
+	// push message
+	ctx.Out.Hundo(result)
+	return nil
+}
+
+// command listing the pokemon hatching from an egg distance
+type eggsCommand struct{}
+
+func (eggsCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "eggs",
+		Description: "Gives you the breakdown for pokemon in each egg.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "distance",
+				Description: "Distance of egg.",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "2 km",
+						Value: "2 km",
+					},
+					{
+						Name:  "5 km",
+						Value: "5 km",
+					},
+					{
+						Name:  "7 km",
+						Value: "7 km",
+					},
+					{
+						Name:  "10 km",
+						Value: "10 km",
+					},
+					{
+						Name:  "12 km",
+						Value: "12 km",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (eggsCommand) Handle(ctx *Context) error {
+	// build response
+	eggs, err := getEggs(ctx.Ctx, ctx.Store, ctx.Data, ctx.String("distance"))
+	if err != nil {
+		return err
+	}
+
+	// push message
+	ctx.Out.Eggs(eggs)
+	return nil
+}
+
+// command listing the current raid bosses
+type raidsCommand struct{}
+
+func (raidsCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "raids",
+		Description: "Gives you all the pokemon in raids.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "raid_tier",
+				Description: "tier name. Examples: tier 5 | all | mega",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Tier 1",
+						Value: "tier 1",
+					},
+					{
+						Name:  "Tier 3",
+						Value: "tier 3",
+					},
+					{
+						Name:  "Tier 5",
+						Value: "tier 5",
+					},
+					{
+						Name:  "Mega",
+						Value: "mega",
+					},
+					{
+						Name:  "All",
+						Value: "all",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (raidsCommand) Handle(ctx *Context) error {
+	// build response
+	raids, err := getRaids(ctx.Ctx, ctx.Store, ctx.Data, ctx.String("raid_tier"))
+	if err != nil {
+		return err
+	}
+
+	// push message
+	ctx.Out.Raids(raids)
+	return nil
+}
+
+// command setting the channel raid, egg and research changes are posted in, server admins only
+type announceCommand struct{}
+
+func (announceCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "announce",
+		Description:              "Post raid, egg and research changes to a channel. Leave empty to stop posting.",
+		DefaultMemberPermissions: &manageServer,
+		DMPermission:             &noDMs,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:         "channel",
+				Description:  "Channel to post changes in",
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+				Required:     false,
+			},
+		},
+	}
+}
+
+func (announceCommand) Ephemeral() bool { return true }
+
+func (announceCommand) Handle(ctx *Context) error {
+	guild := ctx.GuildID
+	if guild == "" {
+		ctx.Reply("Announcements can only be set up in a server.")
+		return nil
+	}
+
+	// no channel given, stop posting
+	channel := ctx.ChannelID("channel")
+	if channel == "" {
+		err := ctx.Store.RemoveAnnounceChannel(ctx.Ctx, guild)
+		if err != nil {
+			return &InternalError{Op: "removing announcement channel", Err: err}
+		}
+		ctx.Reply("Changes will no longer be posted in this server.")
+		return nil
+	}
+
+	err := ctx.Store.SetAnnounceChannel(ctx.Ctx, guild, channel)
+	if err != nil {
+		return &InternalError{Op: "setting announcement channel", Err: err}
+	}
+	ctx.Reply("Raid, egg and research changes will be posted in <#" + channel + ">.")
+	return nil
+}
+
+// command listing the field research tasks for a reward or task type
+type researchCommand struct{}
+
+func (researchCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "research",
+		Description: "Gives you the field research tasks for a reward or task type.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "reward",
+				Description: "Reward pokemon to search for. Examples: skwovet | wooloo",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    false,
+			},
+			{
+				Name:        "type",
+				Description: "Type of task",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Catch",
+						Value: "catch",
+					},
+					{
+						Name:  "Throw",
+						Value: "throw",
+					},
+					{
+						Name:  "Battle",
+						Value: "battle",
+					},
+					{
+						Name:  "Explore",
+						Value: "explore",
+					},
+					{
+						Name:  "Buddy",
+						Value: "buddy",
+					},
+					{
+						Name:  "Rocket",
+						Value: "rocket",
+					},
+					{
+						Name:  "Training",
+						Value: "training",
+					},
+					{
+						Name:  "Event",
+						Value: "event",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (researchCommand) Handle(ctx *Context) error {
+	// both options are optional, but one is needed to search
+	reward := ctx.String("reward")
+	taskType := ctx.String("type")
+	if reward == "" && taskType == "" {
+		ctx.Reply("Give a reward pokemon or a task type to search for.")
+		return nil
+	}
+
+	// build response
+	research, err := getResearch(ctx.Ctx, ctx.Store, ctx.Data, reward, taskType)
+	if err != nil {
+		return err
+	}
+
+	// push message
+	ctx.Out.Research(research)
+	return nil
+}
+
+// command pulling the latest data, server admins only
+type refreshCommand struct{}
+
+func (refreshCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "refresh",
+		Description:              "Pull the latest egg, raid, event and research data.",
+		DefaultMemberPermissions: &manageServer,
+		DMPermission:             &noDMs,
+	}
+}
+
+func (refreshCommand) Ephemeral() bool { return true }
+
+func (refreshCommand) Handle(ctx *Context) error {
+	if ctx.Data == nil {
+		ctx.Reply("Data refreshes are not set up.")
+		return nil
+	}
+	// queue a refresh, the background loop does the work
+	if !ctx.Data.Trigger() {
+		ctx.Reply("A data refresh is already queued.")
+		return nil
+	}
+	ctx.Reply("Data refresh started.")
+	return nil
+}
+
+// command showing the raid, egg and research changes of the latest data update, also after a restart
+type changesCommand struct{}
+
+func (changesCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "changes",
+		Description: "Gives you the raid, egg and research changes from the latest data update.",
+	}
+}
+
+func (changesCommand) Handle(ctx *Context) error {
+	diff, err := LatestChanges(ctx.Ctx, ctx.Store)
+	if err != nil {
+		return &InternalError{Op: "loading latest changes", Err: err}
+	}
+
+	// updates that only changed events have nothing to show here
+	if diff == nil || !diff.RotationChanged() {
+		ctx.Reply("No raid, egg or research changes were recorded yet.")
+		return nil
+	}
+	ctx.Out.Changes(diff)
+	return nil
+}
+
+// command listing every current source of a pokemon
+type whereCommand struct{}
+
+func (whereCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "where",
+		Description: "Gives you every current source for a pokemon.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "pokemon",
+				Description: "Pokemon to search for. Examples: ponyta | absol | larvesta",
+				Type:        discordgo.ApplicationCommandOptionString,
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (whereCommand) Handle(ctx *Context) error {
+	// build response
+	pokemon, sources, err := getWhere(ctx.Ctx, ctx.Store, ctx.Data, ctx.String("pokemon"))
+	if err != nil {
+		return err
+	}
+
+	// push message
+	ctx.Out.Where(pokemon, sources)
+	return nil
+}
+
+// command showing the xp left to reach a level
+type xpCommand struct{}
+
+func (xpCommand) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "xp",
+		Description: "Calculate xp to reach xp goals.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "current_xp",
+				Description: "your current xp",
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Required:    true,
+			},
+			{
+				Name:        "xp_goal",
+				Description: "your current xp",
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Level 40",
+						Value: "20000000",
+					},
+					{
+						Name:  "Level 41",
+						Value: "26000000",
+					},
+					{
+						Name:  "Level 42",
+						Value: "33500000",
+					},
+					{
+						Name:  "Level 43",
+						Value: "42500000",
+					},
+					{
+						Name:  "Level 44",
+						Value: "53500000",
+					},
+					{
+						Name:  "Level 45",
+						Value: "66500000",
+					},
+					{
+						Name:  "Level 46",
+						Value: "82000000",
+					},
+					{
+						Name:  "Level 47",
+						Value: "100000000",
+					},
+					{
+						Name:  "Level 48",
+						Value: "121000000",
+					},
+					{
+						Name:  "Level 49",
+						Value: "146000000",
+					},
+					{
+						Name:  "Level 50",
+						Value: "176000000",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (xpCommand) Handle(ctx *Context) error {
+	// push message
+	ctx.Out.Xp(getXp(ctx.Int("current_xp"), ctx.Int("xp_goal")))
+	return nil
+}
@@ -1,266 +1,108 @@
 // main.go
 // Author: Cade Beckers
 // Written: 08/23/2024
-// Updated: 09/10/2024
+// Updated: 10/18/2026
 
 package main
 
 import (
-	"database/sql"
-	"fmt"
+	"context"
+	"errors"
 	"log"
+	"log/slog"
 	"math"
 	"os"
 	"os/signal"
-	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
-	_ "github.com/go-sql-driver/mysql"
 )
 
 var (
-	GuildID = "" // add your GuildID here (server id)
-	BotToken = "" // add your BotToken here
-	commands = []*discordgo.ApplicationCommand{
-		{
-			Name:        "best",
-			Description: "Gives you the best pokemon for specified category.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "search_setting",
-					Description: "Search method",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Same Type Moves",
-							Value: "sametype",
-						},
-						{
-							Name:  "Mixed Type Moves",
-							Value: "mixtype",
-						},
-						{
-							Name:  "Name",
-							Value: "name",
-						},
-					},
-				},
-				{
-					Name:        "sort_by",
-					Description: "Sorting method",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Damage per Second",
-							Value: "dps",
-						},
-						{
-							Name:  "Total Damage Output",
-							Value: "tdo",
-						},
-						{
-							Name:  "Effectiveness Rating",
-							Value: "er",
-						},
-					},
-				},
-				{
-					Name:        "number_of_results",
-					Description: "Top # of results needed",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Top 10",
-							Value: "10",
-						},
-						{
-							Name:  "Top 5",
-							Value: "5",
-						},
-						{
-							Name:  "Top 3",
-							Value: "3",
-						},
-						{
-							Name:  "Top 1",
-							Value: "1",
-						},
-					},
-				},
-				{
-					Name:        "name_or_type",
-					Description: "Enter pokemon name",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-				},
-			},
-		},
-		{
-			Name:        "hundo",
-			Description: "Gives you the hundo numbers for a specific pokemon.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "pokemon",
-					Description: "Pokemon it search for. Examples: mewtwo | charmander | kartana",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-				},
-			},
-		},
-		{
-			Name:        "eggs",
-			Description: "Gives you the breakdown for pokemon in each egg.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "distance",
-					Description: "Distance of egg.",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "2 km",
-							Value: "2 km",
-						},
-						{
-							Name:  "5 km",
-							Value: "5 km",
-						},
-						{
-							Name:  "7 km",
-							Value: "7 km",
-						},
-						{
-							Name:  "10 km",
-							Value: "10 km",
-						},
-						{
-							Name:  "12 km",
-							Value: "12 km",
-						},
-					},
-				},
-			},
-		},
-		{
-			Name:        "raids",
-			Description: "Gives you all the pokemon in raids.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "raid_tier",
-					Description: "tier name. Examples: tier 5 | all | mega",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Tier 1",
-							Value: "tier 1",
-						},
-						{
-							Name:  "Tier 3",
-							Value: "tier 3",
-						},
-						{
-							Name:  "Tier 5",
-							Value: "tier 5",
-						},
-						{
-							Name:  "Mega",
-							Value: "mega",
-						},
-						{
-							Name:  "All",
-							Value: "all",
-						},
-					},
-				},
-			},
-		},
-		{
-			Name:        "xp",
-			Description: "Calculate xp to reach xp goals.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "current_xp",
-					Description: "your current xp",
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Required:    true,
-				},
-				{
-					Name:        "xp_goal",
-					Description: "your current xp",
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Level 40",
-							Value: "20000000",
-						},
-						{
-							Name:  "Level 41",
-							Value: "26000000",
-						},
-						{
-							Name:  "Level 42",
-							Value: "33500000",
-						},
-						{
-							Name:  "Level 43",
-							Value: "42500000",
-						},
-						{
-							Name:  "Level 44",
-							Value: "53500000",
-						},
-						{
-							Name:  "Level 45",
-							Value: "66500000",
-						},
-						{
-							Name:  "Level 46",
-							Value: "82000000",
-						},
-						{
-							Name:  "Level 47",
-							Value: "100000000",
-						},
-						{
-							Name:  "Level 48",
-							Value: "121000000",
-						},
-						{
-							Name:  "Level 49",
-							Value: "146000000",
-						},
-						{
-							Name:  "Level 50",
-							Value: "176000000",
-						},
-					},
-				},
-			},
-		},
-	}
+	config     *Config
+	dataSource DataSource
+	store      Store
+	refresher  *Refresher
+	registry   *Registry
 )
 
 func main() {
-	// pull new data files on start of bot
-	pullFiles()
+	// "bot query <command> ..." runs one command in the terminal without connecting to discord
+	// "bot export-seeds" writes the stats tables to the csv files the sqlite backend is filled from
+	exporting := len(os.Args) > 1 && os.Args[1] == "export-seeds"
+	offline := exporting || isCLI(os.Args[1:])
+
+	// settings come from config.json and BOT_* environment variables
+	var err error
+	config, err = loadConfig(offline)
+	if err != nil {
+		log.Fatal(err)
+	}
+	config.setupLogging()
+	resolver = NewNameResolver(config.Aliases)
+
+	// pick where the data files come from
+	dataSource, err = newDataSource(config.DataSource, config.DataURL, config.DataBranch, config.DataPath)
+	if err != nil {
+		fatal("Cannot create the data source", "err", err)
+	}
+
+	// one connection pool shared by every command and the data refresh
+	store, err = newStore(config.DatabaseType, config.DatabaseDSN)
+	if err != nil {
+		fatal("Cannot connect to the database", "err", err)
+	}
+	defer store.Close()
+	if exporting {
+		err = exportSeeds(store)
+		store.Close()
+		if err != nil {
+			fatal("Cannot export the seed files", "err", err)
+		}
+		return
+	}
+
+	// every command with its handler, a mistake in a definition stops the bot before it connects
+	registry, err = NewRegistry(botCommands...)
+	if err != nil {
+		fatal("Invalid command definitions", "err", err)
+	}
+
+	// data is only pulled in the terminal when asked for, the database already holds the last import
+	refresher = NewRefresher(store, dataSource, config.RefreshInterval.Duration)
+	if offline {
+		code := runCLI(os.Args[2:], os.Stdout, os.Stderr)
+		store.Close()
+		os.Exit(code)
+	}
 
 	// connect to the bot
-	sess, err := discordgo.New("") //insert bot token in quotes
+	sess, err := discordgo.New(config.BotToken())
 	if err != nil {
-		log.Fatal(err)
+		fatal("Cannot create the discord session", "err", err)
 	}
 
-	// Add a handler for commands
+	// post raid, egg and research changes to the channels set with /announce
+	refresher.OnChange = func(diff *DatasetDiff) {
+		postChanges(sess, store, diff)
+	}
+	// commands are answered from the data already in the database until the first pull is done
+	// the snapshot saved with the last import tells how old that data is
+	refresher.UseSnapshotTime()
+
+	// Add a handler for commands, the suggestions shown while typing them and the page buttons
 	sess.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if i.Type == discordgo.InteractionApplicationCommand {
-			handleCommand(s, i)
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			runCommand(s, i, store, refresher, config.CommandTimeout.Duration)
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			handleAutocomplete(s, i, store, refresher)
+		case discordgo.InteractionMessageComponent:
+			if strings.HasPrefix(i.MessageComponentData().CustomID, pageButtonPrefix) {
+				handlePageButton(s, i)
+			}
 		}
 	})
 
@@ -269,24 +111,41 @@ func main() {
 	// check if bot is online
 	err = sess.Open()
 	if err != nil {
-		log.Fatal(err)
+		fatal("Cannot connect to discord", "err", err)
 	}
 	defer sess.Close()
 
-	// pull all commands
-	for _, cmd := range commands {
-		_, err := sess.ApplicationCommandCreate(sess.State.User.ID, GuildID, cmd)
-		if err != nil {
-			log.Fatalf("Cannot create '%v' command: %v", cmd.Name, err)
-		}
+	// register the commands in each guild and/or globally, renamed and deleted commands are removed
+	scopes := config.CommandScopes()
+	err = syncCommands(sess, sess.State.User.ID, scopes, registry.Definitions())
+	if err != nil {
+		fatal("Cannot register commands", "err", err)
 	}
 
-	println("The bot is online")
+	// pull new data in the background, the first pull starts right away
+	// a failed pull is not fatal, the bot keeps serving the last good data
+	defer refresher.Stop()
+	go refresher.Run()
+
+	slog.Info("The bot is online", "user", sess.State.User.Username)
 
 	// check for termination signal
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
+
+	if config.RemoveCommands {
+		err = removeCommands(sess, sess.State.User.ID, scopes)
+		if err != nil {
+			slog.Error("Error removing commands on shutdown", "err", err)
+		}
+	}
+}
+
+// func to log an error and stop the bot, deferred calls are skipped like with log.Fatal
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
 
 // add a space after "," for better formating
@@ -296,78 +155,141 @@ func addSpace(input string) string {
 }
 
 // convert input from database into yes/no
-func convertBool(input string) string {
-	if input == "1" {
+func convertBool(input bool) string {
+	if input {
 		return "yes"
 	}
 	return "no"
 }
 
-// func for getting best attackers
-func getBest(s *discordgo.Session, search string, sort string, num string, name_type string) string {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
+// struct for the result of a best attackers search
+type BestResult struct {
+	Search    string // "sametype", "mixtype" or "name"
+	Sort      string // "dps", "tdo" or "er"
+	Name      string // the pokemon or type searched for, as the data writes it
+	Image     string // sprite of the pokemon for name searches
+	Attackers []Attacker
+}
 
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
+// func to describe what was searched, eg. "Best Fire attackers by DPS"
+func (b *BestResult) Title() string {
+	sortLabel := map[string]string{"dps": "DPS", "tdo": "TDO", "er": "rating"}[b.Sort]
+	switch b.Search {
+	case "name":
+		return "Best " + b.Name + " movesets by " + sortLabel
+	case "mixtype":
+		return "Best " + b.Name + " charged moves by " + sortLabel
 	}
-	defer db.Close()
+	return "Best " + b.Name + " attackers by " + sortLabel
+}
 
-	// ping the database to verify the connection
-	err = db.Ping()
+// struct for the cp of a pokemon with perfect ivs at each level the hundo command shows
+type HundoResult struct {
+	Pokemon PokemonStats
+	Image   string // sprite from the current data, empty if there is none
+	Levels  []LevelCP
+}
+
+// struct for the cp at one level
+type LevelCP struct {
+	Label string
+	CP    int
+}
+
+// struct for the progress towards an xp goal
+type XpProgress struct {
+	Level     string // level the goal reaches, eg. "Level 40"
+	Remaining int64
+	Percent   float64 // share of the goal already gained, rounded to 2 decimals
+}
+
+// levels the hundo command shows the cp for
+var hundoLevels = []struct {
+	mult  float64
+	label string
+}{
+	{0.51739395, "Field Research"},
+	{0.5974, "Eggs / Raid no WB"},
+	{0.667934, "Raid with WB"},
+	{0.7317, "Wild no WB"},
+	{0.76156384, "Wild with WB"},
+	{0.7903, "Level 40"},
+	{0.84029999, "Level 50"},
+}
+
+// func for getting best attackers
+func getBest(ctx context.Context, db Store, search string, sort string, num string, name_type string) (*BestResult, error) {
+	// an sqlite database has no attackers until its seed files are added
+	loaded, err := db.HasAttackers(ctx)
 	if err != nil {
-		panic(err)
+		return nil, &InternalError{Op: "loading best attackers", Err: err}
 	}
-
-	// build the sql query for all collected data
-	query := "SELECT * FROM newdps2 " + search + "WHERE  ORDER BY " + sort + " DESC LIMIT " + num + ";"
-	switch search {
-	case "name":
-		query = "SELECT * FROM newdps2 WHERE name=\"" + name_type + "\" ORDER BY " + sort + " DESC LIMIT " + num + ";"
-	case "sametype":
-		query = "SELECT * FROM newdps2 WHERE ftype=\"" + name_type + "\" AND ctype=\"" + name_type + "\"  ORDER BY " + sort + " DESC LIMIT " + num + ";"
-	case "mixtype":
-		query = "SELECT * FROM newdps2 WHERE ctype=\"" + name_type + "\"  ORDER BY " + sort + " DESC LIMIT " + num + ";"
+	if !loaded {
+		return nil, &DataUnavailableError{What: "best attacker"}
 	}
 
-	// execute query
-	rows, err := db.Query(query)
+	// catch typos in the name or type before searching
+	if search == "name" {
+		name_type, err = resolvePokemon(ctx, db, name_type)
+	} else {
+		name_type, err = resolveType(name_type)
+	}
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	// count row amount for ranking number and start the message string
-	count := 0
-	var msg string = ""
+	// a known pokemon or type can still have no attackers worth listing, the result is empty then
+	attackers, err := db.BestAttackers(ctx, search, sort, num, name_type)
+	if err != nil {
+		return nil, &InternalError{Op: "loading best attackers", Err: err}
+	}
 
-	// output each row pulled and format it
-	for rows.Next() {
-		count++
-		var name string
-		var fmove string
-		var ftype string
-		var cmove string
-		var ctype string
-		var dps string
-		var tdo string
-		var er string
-		var cp string
-
-		err = rows.Scan(&name, &fmove, &ftype, &cmove, &ctype, &dps, &tdo, &er, &cp)
+	result := &BestResult{Search: search, Sort: sort, Name: name_type, Attackers: attackers}
+	// name searches show the pokemon
+	if search == "name" {
+		result.Image, err = db.PokemonImage(ctx, name_type)
 		if err != nil {
-			panic(err)
+			return nil, &InternalError{Op: "loading pokemon image", Err: err}
 		}
-		strcount := strconv.Itoa(count)
-		var tt string = "Rank " + strcount + " : **" + name + "**\n**" + fmove + "** (" + ftype + ") / **" + cmove +
-			"** (" + ctype + ")\nDPS: **" + dps + "**  |  TDO: **" + tdo + "**  |  Rating: " + er + "  |  CP: " + cp + "\n\n"
-		msg = msg + tt
 	}
+	return result, nil
+}
+
+// func to get the pokemon name the user meant, eg. "galar meowth" gives "Galarian Meowth"
+// unknown names give a not found error suggesting the closest known name
+func resolvePokemon(ctx context.Context, db Store, input string) (string, error) {
+	input = strings.TrimSpace(input)
+	names, err := db.PokemonNames(ctx)
+	if err != nil {
+		return "", &InternalError{Op: "loading pokemon names", Err: err}
+	}
+	if len(names) == 0 {
+		return "", &DataUnavailableError{What: "Pokémon stats"}
+	}
+	name, ok := resolver.Resolve(input, names)
+	if !ok {
+		return "", &NotFoundError{Kind: "Pokémon", Name: input, Suggestion: name}
+	}
+	return name, nil
+}
+
+// func to get the pokemon type the user meant
+func resolveType(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	name, ok := resolver.Resolve(input, pokemonTypes)
+	if !ok {
+		return "", &NotFoundError{Kind: "type", Name: input, Suggestion: name}
+	}
+	return name, nil
+}
 
-	// close db and send message
-	db.Close()
-	return msg
+// func to get a data unavailable error for an empty result when no data was imported yet
+// data is nil when nothing refreshes the store, eg. a test store, the result is taken as it is then
+func checkLoaded(data *Refresher, count int, what string) error {
+	if count == 0 && data != nil && data.Status().LastSuccess.IsZero() {
+		return &DataUnavailableError{What: what}
+	}
+	return nil
 }
 
 // func to calculate cp (combat power)
@@ -378,190 +300,127 @@ func getCP(mult float64, hp int, attack int, defense int) int {
 }
 
 // func for getting the current pokemon pool for eggs
-func getEggs(s *discordgo.Session, egg_distance string) string {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
-
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
+func getEggs(ctx context.Context, db Store, data *Refresher, egg_distance string) ([]Egg, error) {
+	eggs, err := db.Eggs(ctx, egg_distance)
 	if err != nil {
-		panic(err)
+		return nil, &InternalError{Op: "loading eggs", Err: err}
 	}
-	defer db.Close()
-
-	// ping the database to verify the connection
-	err = db.Ping()
+	err = checkLoaded(data, len(eggs), "egg")
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return eggs, nil
+}
 
-	// build and execute query
-	query := "SELECT * FROM eggs WHERE distance=\"" + egg_distance + "\";"
-
-	rows, err := db.Query(query)
+// func to get all the relevant hundo numbers for a specific pokemon
+func getHundo(ctx context.Context, db Store, pokemon string) (*HundoResult, error) {
+	name, err := resolvePokemon(ctx, db, pokemon)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	p, err := db.Hundo(ctx, name)
+	if err != nil {
+		return nil, &InternalError{Op: "loading pokemon stats", Err: err}
+	}
+	if p == nil {
+		return nil, &DataUnavailableError{What: "Pokémon stats"}
 	}
-	defer rows.Close()
-
-	// vars for message
-	var name string
-	var distance string
-	var adventure_sync string
-	var image string
-	var shiny string
-	var min_cp string
-	var max_cp string
-	var regional string
-
-	// add message header and then build the message
-	msg := "**Eggs:**\n"
-
-	for rows.Next() {
-		err = rows.Scan(&name, &distance, &adventure_sync, &image, &shiny, &min_cp, &max_cp, &regional)
-		if err != nil {
-			panic(err)
-		}
-		// create header text and each row of the message
-		adventure_sync = convertBool(adventure_sync)
-		shiny = convertBool(shiny)
-		regional = convertBool(regional)
 
-		msg = msg + "**" + name + " : " + distance + "**   cp: **" + min_cp + "-" + max_cp + "**   Shiny: " + shiny + "   Adventure sync: " + adventure_sync + "\n"
+	// the stats table has no images, use the sprite from the current eggs, raids, research or events
+	image, err := db.PokemonImage(ctx, p.Name)
+	if err != nil {
+		return nil, &InternalError{Op: "loading pokemon image", Err: err}
 	}
 
-	// close connection and send message
-	db.Close()
-	return msg
+	result := &HundoResult{Pokemon: *p, Image: image}
+	for _, level := range hundoLevels {
+		result.Levels = append(result.Levels, LevelCP{Label: level.label, CP: getCP(level.mult, p.HP, p.Attack, p.Defense)})
+	}
+	return result, nil
 }
 
-// func to get all the relevant hundo numbers for a specific pokemon
-func getHundo(s *discordgo.Session, pokemon string) string {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
-
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
+// func to get the live or upcoming events, optionally of a single type
+func getEvents(ctx context.Context, db Store, data *Refresher, when string, event_type string) ([]Event, error) {
+	events, err := db.Events(ctx, when, event_type, time.Now())
 	if err != nil {
-		panic(err)
+		return nil, &InternalError{Op: "loading events", Err: err}
 	}
-	defer db.Close()
-
-	// ping the database to verify the connection
-	err = db.Ping()
+	err = checkLoaded(data, len(events), "event")
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return events, nil
+}
 
-	// prepare the sql query
-	query := "SELECT * FROM pokemon_data WHERE name=\"" + pokemon + "\";"
-	rows, err := db.Query(query)
-	if err != nil {
-		panic(err)
-	}
-	defer rows.Close()
-
-	//declare all data for message building and constants for calculation
-	var name string
-	var hp int
-	var attack int
-	var defense int
-	// constants for calculating cp at specific level
-	const m15 = 0.51739395
-	const m20 = 0.5974
-	const m25 = 0.667934
-	const m30 = 0.7317
-	const m35 = 0.76156384
-	const m40 = 0.7903
-	const m50 = 0.84029999
-
-	var msg string
-
-	// pull each row of the result
-	for rows.Next() {
-		err = rows.Scan(&name, &hp, &attack, &defense)
-		if err != nil {
-			panic(err)
+// func to get the field research tasks matching a reward and/or task type, at least one has to be given
+func getResearch(ctx context.Context, db Store, data *Refresher, reward string, task_type string) ([]ResearchReward, error) {
+	// search by the full pokemon name when the reward is one, eg. "flabebe" finds "Flabébé"
+	// new pokemon can be rewards before the stats table has them, so unknown names are searched as typed
+	reward = strings.TrimSpace(reward)
+	var resolveErr error
+	if reward != "" {
+		var name string
+		name, resolveErr = resolvePokemon(ctx, db, reward)
+		if resolveErr == nil {
+			reward = name
 		}
-		// create header text and each row of the message
-		msg = "Pokemon: **" + name + "**\n"
-
-		msg = msg + "**" + strconv.Itoa(getCP(m15, hp, attack, defense)) + "** - Field Research\n"
-		msg = msg + "**" + strconv.Itoa(getCP(m20, hp, attack, defense)) + "** - Eggs / Raid no WB\n"
-		msg = msg + "**" + strconv.Itoa(getCP(m25, hp, attack, defense)) + "** - Raid with WB\n"
-		msg = msg + "**" + strconv.Itoa(getCP(m30, hp, attack, defense)) + "** - Wild no WB\n"
-		msg = msg + "**" + strconv.Itoa(getCP(m35, hp, attack, defense)) + "** - Wild with WB\n"
-		msg = msg + "**" + strconv.Itoa(getCP(m40, hp, attack, defense)) + "** - Level 40\n"
-		msg = msg + "**" + strconv.Itoa(getCP(m50, hp, attack, defense)) + "** - Level 50\n"
-	}
-	// close connection and send message
-	db.Close()
-	return msg
+	}
+
+	research, err := db.Research(ctx, reward, task_type)
+	if err != nil {
+		return nil, &InternalError{Op: "loading research", Err: err}
+	}
+	err = checkLoaded(data, len(research), "research")
+	if err != nil {
+		return nil, err
+	}
+	var notFound *NotFoundError
+	if len(research) == 0 && errors.As(resolveErr, &notFound) {
+		return nil, resolveErr
+	}
+	return research, nil
 }
 
 // func to get the current raid pool
-func getRaids(s *discordgo.Session, raid_tier string) string {
-	dsn := "root:mysql@tcp(127.0.0.1:3306)/pogodb"
-
-	// open a connection to the database
-	db, err := sql.Open("mysql", dsn)
+func getRaids(ctx context.Context, db Store, data *Refresher, raid_tier string) ([]Raid, error) {
+	raids, err := db.Raids(ctx, raid_tier)
 	if err != nil {
-		panic(err)
+		return nil, &InternalError{Op: "loading raids", Err: err}
 	}
-	defer db.Close()
-
-	// ping the database to verify the connection
-	err = db.Ping()
+	err = checkLoaded(data, len(raids), "raid")
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return raids, nil
+}
 
-	var query string
-
-	// build query
-	query = "SELECT * FROM raids WHERE tier=\"" + raid_tier + "\";"
-	if raid_tier == "all" {
-		query = "SELECT * FROM raids;"
+// func to get every current source for a pokemon from eggs, raids, research and events
+// returns the pokemon name as the data writes it with its sources
+func getWhere(ctx context.Context, db Store, data *Refresher, pokemon string) (string, []PokemonSource, error) {
+	// new pokemon can show up in the data before the stats table has them, so search unknown names as typed
+	pokemon = strings.TrimSpace(pokemon)
+	name, resolveErr := resolvePokemon(ctx, db, pokemon)
+	if resolveErr == nil {
+		pokemon = name
 	}
 
-	rows, err := db.Query(query)
+	sources, err := db.Where(ctx, pokemon, time.Now())
 	if err != nil {
-		panic(err)
-	}
-	defer rows.Close()
-
-	// vars for message building
-	var name string
-	var tier string
-	var shiny string
-	var types string
-	var min_cp string
-	var max_cp string
-	var wb_min_cp string
-	var wb_max_cp string
-	var boosted_weather string
-	var image string
-
-	// create header and then format each raid
-	msg := "**Raids:**\n"
-
-	for rows.Next() {
-		err = rows.Scan(&name, &tier, &shiny, &types, &min_cp, &max_cp, &wb_min_cp, &wb_max_cp, &boosted_weather, &image)
-		if err != nil {
-			panic(err)
-		}
-		// create header text and each row of the message
-		shiny = convertBool(shiny)
-		types = addSpace(types)
-		boosted_weather = addSpace(boosted_weather)
-
-		msg = msg + "**" + name + " : " + tier + "**   Shiny: **" + shiny + "**   cp: **" + min_cp + "-" + max_cp + " | " + wb_min_cp + " - " + wb_max_cp + "**\n"
+		return "", nil, &InternalError{Op: "loading pokemon sources", Err: err}
+	}
+	err = checkLoaded(data, len(sources), "pokemon")
+	if err != nil {
+		return "", nil, err
+	}
+	var notFound *NotFoundError
+	if len(sources) == 0 && errors.As(resolveErr, &notFound) {
+		return "", nil, resolveErr
 	}
-	// close connection and send message
-	db.Close()
-	return msg
+	return pokemon, sources, nil
 }
 
 // func to calculate progression towards xp (experience points) landmarks
-func getXp(s *discordgo.Session, current_xp int64, goal_xp int64) string {
+func getXp(current_xp int64, goal_xp int64) XpProgress {
 	var level string = ""
 	switch goal_xp {
 	case 20000000:
@@ -591,124 +450,7 @@ func getXp(s *discordgo.Session, current_xp int64, goal_xp int64) string {
 	// calc percent of completion towards and xp goal
 	remaining := goal_xp - current_xp
 	percent := roundToDecimal(((float64(current_xp) / float64(goal_xp)) * 100), 2)
-	str_percent := fmt.Sprintf("%.2f", percent)
-
-	// build and send message
-	var msg string = "Xp to " + level + ": **" + strconv.Itoa(int(remaining)) + "**  |  Percent of xp gained: **" + str_percent + "%**"
-	return msg
-}
-
-// func to handle and create commands using "/" on the discord end
-func handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// switch for each command
-	switch i.ApplicationCommandData().Name {
-	case "best":
-		// Get the user inputs from the options
-		search := i.ApplicationCommandData().Options[0].StringValue()
-		sort := i.ApplicationCommandData().Options[1].StringValue()
-		num := i.ApplicationCommandData().Options[2].StringValue()
-		name_type := i.ApplicationCommandData().Options[3].StringValue()
-
-		// build response
-		response := getBest(s, search, sort, num, name_type)
-
-		// push message
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: response,
-			},
-		})
-	case "hundo":
-		// Get the user inputs from the options
-		pokemon := i.ApplicationCommandData().Options[0].StringValue()
-
-		// build response
-		response := getHundo(s, pokemon)
-
-		// push message
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: response,
-			},
-		})
-	case "eggs":
-		// Get the user inputs from the options
-		distance := i.ApplicationCommandData().Options[0].StringValue()
-
-		// build response
-		response := getEggs(s, distance)
-
-		// push message
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: response,
-			},
-		})
-	case "raids":
-		// Get the user inputs from the options
-		raid_tier := i.ApplicationCommandData().Options[0].StringValue()
-
-		// build response
-		response := getRaids(s, raid_tier)
-
-		// push message
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: response,
-			},
-		})
-	case "xp":
-		// Get the user inputs from the options
-		current_xp := i.ApplicationCommandData().Options[0].IntValue()
-		goal_xp := i.ApplicationCommandData().Options[1].IntValue()
-
-		// build response
-		response := getXp(s, current_xp, goal_xp)
-
-		// push message
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: response,
-			},
-		})
-	}
-	// refresh files after sending the response to user
-	pullFiles()
-}
-
-func pullFiles() {
-	// repo url
-	repoURL := "https://github.com/bigfoott/ScrapedDuck.git"
-
-	// folder locations
-	clonePath := "./data"
-	outputPath := "./ScrapedDuck"
-
-	// delete old files
-	os.RemoveAll(clonePath)
-	os.RemoveAll(outputPath)
-	os.Mkdir(outputPath, os.ModePerm)
-
-	// clone repo
-	err := CloneRepo(repoURL, clonePath)
-	if err != nil {
-		log.Fatalf("Error cloning repository: %v", err)
-	}
-
-	// copy files from the repo
-	err = CopyFilesFromBranch(clonePath, "data", outputPath)
-	if err != nil {
-		log.Fatalf("Error copying files: %v", err)
-	}
-
-	fmt.Println("Files copied successfully to", outputPath)
-	// refresh the database with the new pulled data
-	refreshDB()
+	return XpProgress{Level: level, Remaining: remaining, Percent: percent}
 }
 
 // round the given float64 to _ decimal places
@@ -721,6 +463,4 @@ func roundToDecimal(f float64, decimals int) float64 {
 func sendMessage(s *discordgo.Session, m *discordgo.MessageCreate, msg string) {
 	// send message to channel
 	s.ChannelMessageSend(m.ChannelID, msg)
-	// refresh files after message
-	pullFiles()
 }
@@ -0,0 +1,233 @@
+// migrations.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// struct for one versioned change to the database schema
+type migration struct {
+	version int
+	name    string
+	up      func(m *migrator) error
+}
+
+// schema changes in the order they are applied, never edit or reorder a released migration
+// every step also works on databases that already have the change, eg. ones set up by hand
+var migrations = []migration{
+	{1, "create pogodb tables", createPogodbTables},
+	{2, "name pokemon_data columns", namePokemonDataColumns},
+	{3, "add event schedule and details", addEventDetails},
+	{4, "add dataset changes and announce channels", createBotTables},
+}
+
+// struct to run migrations on one database, holding the bits of sql that differ per dialect
+type migrator struct {
+	db      *sql.DB
+	dialect string
+	nocase  string // collation making text matches ignore case, mysql already does by default
+	autoID  string // auto incrementing primary key column
+}
+
+// func to bring the database schema up to the latest migration
+func migrate(db *sql.DB, dialect string) error {
+	m := &migrator{db: db, dialect: dialect}
+	switch dialect {
+	case "mysql":
+		m.autoID = "INT AUTO_INCREMENT PRIMARY KEY"
+	case "sqlite":
+		m.nocase = "COLLATE NOCASE"
+		m.autoID = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	default:
+		return fmt.Errorf("unknown database dialect %q", dialect)
+	}
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at DATETIME NOT NULL)`)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range migrations {
+		if step.version <= current {
+			continue
+		}
+		slog.Info("Applying migration", "version", step.version, "name", step.name)
+		// mysql commits ddl right away so the steps can't run in a transaction
+		// a step that fails halfway is safe to rerun since every step checks what already exists
+		err = step.up(m)
+		if err != nil {
+			return fmt.Errorf("error applying migration %d (%s): %w", step.version, step.name, err)
+		}
+		_, err = db.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			step.version, step.name, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// func to run statements in order, stopping at the first error
+func (m *migrator) exec(statements ...string) error {
+	for _, statement := range statements {
+		_, err := m.db.Exec(statement)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// func to check if a table has a column
+func (m *migrator) hasColumn(table string, column string) (bool, error) {
+	query := `SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?`
+	if m.dialect == "sqlite" {
+		query = "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+	}
+
+	var count int
+	err := m.db.QueryRow(query, table, column).Scan(&count)
+	return count > 0, err
+}
+
+// func to add a column unless the table already has it
+func (m *migrator) addColumn(table string, column string, definition string) error {
+	exists, err := m.hasColumn(table, column)
+	if err != nil || exists {
+		return err
+	}
+	return m.exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+}
+
+// func to rename a column if the table still has it under the old name
+func (m *migrator) renameColumn(table string, oldName string, newName string) error {
+	exists, err := m.hasColumn(table, oldName)
+	if err != nil || !exists {
+		return err
+	}
+	// both mysql and sqlite accept backtick quoted names
+	return m.exec("ALTER TABLE " + table + " RENAME COLUMN `" + oldName + "` TO " + newName)
+}
+
+// migration 1, the tables of the original pogodb database
+func createPogodbTables(m *migrator) error {
+	return m.exec(
+		`CREATE TABLE IF NOT EXISTS eggs (
+			name VARCHAR(255) `+m.nocase+` NOT NULL,
+			distance VARCHAR(8) `+m.nocase+` NOT NULL,
+			adventure_sync BOOL NOT NULL,
+			image VARCHAR(255) NOT NULL,
+			shiny BOOL NOT NULL,
+			min_cp INT NOT NULL,
+			max_cp INT NOT NULL,
+			regional BOOL NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS raids (
+			name VARCHAR(255) `+m.nocase+` NOT NULL,
+			tier VARCHAR(32) `+m.nocase+` NOT NULL,
+			shiny BOOL NOT NULL,
+			types VARCHAR(255) NOT NULL,
+			min_cp INT NOT NULL,
+			max_cp INT NOT NULL,
+			wb_min_cp INT NOT NULL,
+			wb_max_cp INT NOT NULL,
+			boosted_weather VARCHAR(255) NOT NULL,
+			image VARCHAR(255) NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS events (
+			event_id VARCHAR(255) NULL,
+			name VARCHAR(255) NULL,
+			event_type VARCHAR(32) `+m.nocase+` NULL,
+			heading VARCHAR(255) NULL,
+			link VARCHAR(255) NULL,
+			image VARCHAR(255) NULL)`,
+		`CREATE TABLE IF NOT EXISTS researches (
+			text VARCHAR(512) NOT NULL,
+			type VARCHAR(32) `+m.nocase+` NOT NULL,
+			reward VARCHAR(64) `+m.nocase+` NOT NULL,
+			shiny BOOL NOT NULL,
+			min_cp INT NOT NULL,
+			max_cp INT NOT NULL,
+			image VARCHAR(255) NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS pokemon_data (
+			name VARCHAR(255) `+m.nocase+` NULL,
+			hp INT NULL,
+			attack INT NULL,
+			defense INT NULL)`,
+		`CREATE TABLE IF NOT EXISTS newdps2 (
+			name VARCHAR(255) `+m.nocase+` NOT NULL,
+			fmove VARCHAR(64) NOT NULL,
+			ftype VARCHAR(16) `+m.nocase+` NOT NULL,
+			cmove VARCHAR(64) NOT NULL,
+			ctype VARCHAR(16) `+m.nocase+` NOT NULL,
+			dps DECIMAL(6,2) NOT NULL,
+			tdo DECIMAL(8,2) NOT NULL,
+			er DECIMAL(6,2) NOT NULL,
+			cp INT NOT NULL)`,
+	)
+}
+
+// migration 2, pokemon_data was imported from a csv without headers so its columns are "COL 1" to "COL 4"
+func namePokemonDataColumns(m *migrator) error {
+	names := [4]string{"name", "hp", "attack", "defense"}
+	for i, name := range names {
+		err := m.renameColumn("pokemon_data", fmt.Sprintf("COL %d", i+1), name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migration 3, event start and end times plus the featured pokemon and bonuses of each event
+func addEventDetails(m *migrator) error {
+	err := m.addColumn("events", "start_time", "DATETIME NULL")
+	if err != nil {
+		return err
+	}
+	err = m.addColumn("events", "end_time", "DATETIME NULL")
+	if err != nil {
+		return err
+	}
+	return m.exec(
+		`CREATE TABLE IF NOT EXISTS event_pokemon (
+			event_id VARCHAR(255) NOT NULL,
+			role VARCHAR(16) NOT NULL,
+			name VARCHAR(255) `+m.nocase+` NOT NULL,
+			image VARCHAR(255) NOT NULL,
+			shiny BOOL NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS event_bonuses (
+			event_id VARCHAR(255) NOT NULL,
+			text VARCHAR(512) NOT NULL,
+			image VARCHAR(255) NOT NULL,
+			disclaimer BOOL NOT NULL)`,
+	)
+}
+
+// migration 4, tables for the rotation announcements
+func createBotTables(m *migrator) error {
+	return m.exec(
+		`CREATE TABLE IF NOT EXISTS dataset_changes (
+			id `+m.autoID+`,
+			created_at DATETIME NOT NULL,
+			changes MEDIUMTEXT NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS announce_channels (
+			guild_id VARCHAR(32) PRIMARY KEY,
+			channel_id VARCHAR(32) NOT NULL)`,
+	)
+}
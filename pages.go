@@ -0,0 +1,267 @@
+// pages.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discord limits for one message
+const (
+	maxContentLength = 2000 // characters of message text
+	maxEmbeds        = 10   // embeds in one message
+	maxEmbedsLength  = 6000 // characters across all embeds of a message
+)
+
+// how long the pages of a reply can be flipped through after the command
+const pagesTTL = 15 * time.Minute
+
+// prefix of the custom id of the page buttons, followed by the reply id and page number
+const pageButtonPrefix = "page:"
+
+// struct for one page of a reply, either text or embeds
+type page struct {
+	content string
+	embeds  []*discordgo.MessageEmbed
+}
+
+// struct for the pages of one reply
+type pageSet struct {
+	pages   []page
+	owner   string // id of the user who ran the command, only they can flip the pages
+	expires time.Time
+}
+
+// pages of recent replies by the id of the interaction that created them
+var (
+	pageSetsMu sync.Mutex
+	pageSets   = map[string]*pageSet{}
+)
+
+// func to split text into pages at paragraph breaks, footer is added to every page
+func textPages(text string, footer string) []page {
+	limit := maxContentLength - len(footer)
+	var pages []page
+	current := ""
+	flush := func() {
+		if current != "" {
+			pages = append(pages, page{content: current + footer})
+			current = ""
+		}
+	}
+
+	for _, part := range splitText(text, limit) {
+		if len(current)+len(part) > limit {
+			flush()
+		}
+		current = current + part
+	}
+	flush()
+	if len(pages) == 0 {
+		pages = append(pages, page{content: footer})
+	}
+	return pages
+}
+
+// func to cut text into parts that each fit the limit, keeping whole paragraphs and lines where possible
+func splitText(text string, limit int) []string {
+	var parts []string
+	for _, paragraph := range strings.SplitAfter(text, "\n\n") {
+		if len(paragraph) <= limit {
+			parts = append(parts, paragraph)
+			continue
+		}
+		for _, line := range strings.SplitAfter(paragraph, "\n") {
+			// a single line over the limit is cut wherever it has to be
+			for len(line) > limit {
+				cut := limit
+				// don't cut through a multi byte character
+				for cut > 0 && !utf8.RuneStart(line[cut]) {
+					cut--
+				}
+				parts = append(parts, line[:cut])
+				line = line[cut:]
+			}
+			parts = append(parts, line)
+		}
+	}
+	return parts
+}
+
+// func to group embeds into pages within the embed count and length limits
+// room is left on each page for the data freshness footer
+func embedPages(embeds []*discordgo.MessageEmbed) []page {
+	limit := maxEmbedsLength - freshnessRoom
+	var pages []page
+	var current []*discordgo.MessageEmbed
+	length := 0
+	for _, embed := range embeds {
+		size := embedLength(embed)
+		if len(current) == maxEmbeds || (len(current) > 0 && length+size > limit) {
+			pages = append(pages, page{embeds: current})
+			current = nil
+			length = 0
+		}
+		current = append(current, embed)
+		length += size
+	}
+	if len(current) > 0 {
+		pages = append(pages, page{embeds: current})
+	}
+	return pages
+}
+
+// func to count the characters of an embed the way discord does for its size limit
+func embedLength(embed *discordgo.MessageEmbed) int {
+	length := len([]rune(embed.Title)) + len([]rune(embed.Description))
+	for _, field := range embed.Fields {
+		length += len([]rune(field.Name)) + len([]rune(field.Value))
+	}
+	if embed.Footer != nil {
+		length += len([]rune(embed.Footer.Text))
+	}
+	if embed.Author != nil {
+		length += len([]rune(embed.Author.Name))
+	}
+	return length
+}
+
+// func to reply with the first page, adding page buttons if there is more than one
+func respondPages(r *reply, pages []page) {
+	id := r.i.ID
+	if len(pages) > 1 {
+		savePages(id, &pageSet{pages: pages, owner: interactionUser(r.i), expires: time.Now().Add(pagesTTL)})
+	}
+	r.send(pageData(id, pages, 0))
+}
+
+// func to show another page when a page button is clicked
+func handlePageButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// custom id is "page:<reply id>:<page number>"
+	parts := strings.Split(strings.TrimPrefix(i.MessageComponentData().CustomID, pageButtonPrefix), ":")
+	if len(parts) != 2 {
+		return
+	}
+	id := parts[0]
+	index, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return
+	}
+
+	pageSetsMu.Lock()
+	set := pageSets[id]
+	pageSetsMu.Unlock()
+
+	reply := ""
+	switch {
+	case set == nil || time.Now().After(set.expires):
+		reply = "These results have expired, run the command again to see them."
+	case set.owner != interactionUser(i):
+		reply = "Only the person who ran the command can change pages."
+	case index < 0 || index >= len(set.pages):
+		reply = "That page doesn't exist, run the command again to see the results."
+	}
+	if reply != "" {
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: reply,
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	} else {
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: pageData(id, set.pages, index),
+		})
+	}
+	if err != nil {
+		slog.Error("Error changing page", "err", err)
+	}
+}
+
+// func to build the message data of a page with its buttons
+func pageData(id string, pages []page, index int) *discordgo.InteractionResponseData {
+	p := pages[index]
+	data := &discordgo.InteractionResponseData{
+		Content: p.content,
+		// an empty list clears the embeds of the previous page
+		Embeds: append([]*discordgo.MessageEmbed{}, p.embeds...),
+	}
+	if len(pages) == 1 {
+		return data
+	}
+
+	prefix := pageButtonPrefix + id + ":"
+	data.Components = []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: prefix + strconv.Itoa(index-1),
+					Disabled: index == 0,
+				},
+				discordgo.Button{
+					Label:    strconv.Itoa(index+1) + " / " + strconv.Itoa(len(pages)),
+					Style:    discordgo.SecondaryButton,
+					CustomID: prefix + "count",
+					Disabled: true,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.PrimaryButton,
+					CustomID: prefix + strconv.Itoa(index+1),
+					Disabled: index == len(pages)-1,
+				},
+			},
+		},
+	}
+	return data
+}
+
+// func to keep the pages of a reply, dropping the ones that expired
+func savePages(id string, set *pageSet) {
+	pageSetsMu.Lock()
+	defer pageSetsMu.Unlock()
+
+	now := time.Now()
+	for key, old := range pageSets {
+		if now.After(old.expires) {
+			delete(pageSets, key)
+		}
+	}
+	pageSets[id] = set
+}
+
+// func to get the id of the user behind an interaction, in a server or a dm
+func interactionUser(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// func to describe an interaction for log messages
+func interactionName(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		return i.ApplicationCommandData().Name
+	case discordgo.InteractionMessageComponent:
+		return i.MessageComponentData().CustomID
+	}
+	return i.Type.String()
+}
@@ -0,0 +1,123 @@
+// pages_test.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// func to check every text page fits a message, ends with the footer and that no text was lost
+func checkTextPages(t *testing.T, pages []page, text string, footer string) {
+	t.Helper()
+	joined := ""
+	for n, p := range pages {
+		if len(p.content) > maxContentLength {
+			t.Errorf("page %d is %d bytes, over the %d limit", n, len(p.content), maxContentLength)
+		}
+		if !utf8.ValidString(p.content) {
+			t.Errorf("page %d cuts through a character", n)
+		}
+		if !strings.HasSuffix(p.content, footer) {
+			t.Errorf("page %d does not end with the footer", n)
+		}
+		joined = joined + strings.TrimSuffix(p.content, footer)
+	}
+	if joined != text {
+		t.Error("the pages do not add up to the text")
+	}
+}
+
+func TestTextPagesWithFooter(t *testing.T) {
+	footer := "\n*Data is stale since <t:1760000000:R>, the latest refresh failed.*"
+	var b strings.Builder
+	for n := 0; n < 120; n++ {
+		b.WriteString("**Hatch an Egg** (explore)\n  Galarian Meowth   cp: 409-443   Shiny: yes\n\n")
+	}
+	text := b.String()
+
+	pages := textPages(text, footer)
+	if len(pages) < 2 {
+		t.Fatalf("%d characters gave %d page, want more than one", len(text), len(pages))
+	}
+	checkTextPages(t, pages, text, footer)
+	// paragraphs are kept whole
+	for n, p := range pages {
+		if !strings.HasSuffix(strings.TrimSuffix(p.content, footer), "\n\n") {
+			t.Errorf("page %d splits a paragraph", n)
+		}
+	}
+}
+
+func TestTextPagesLongLine(t *testing.T) {
+	// one line with no breaks, in two byte characters so a cut at the byte limit lands inside one
+	text := strings.Repeat("é", 2500) + "x" + strings.Repeat("é", 1000)
+	pages := textPages(text, "")
+	if len(pages) < 3 {
+		t.Fatalf("%d bytes gave %d pages, want at least 3", len(text), len(pages))
+	}
+	checkTextPages(t, pages, text, "")
+}
+
+func TestTextPagesEmpty(t *testing.T) {
+	pages := textPages("", "footer")
+	if len(pages) != 1 || pages[0].content != "footer" {
+		t.Errorf("textPages of no text = %+v, want one page with the footer", pages)
+	}
+}
+
+// func to make n embeds with a description of the given length
+func testEmbeds(n int, length int) []*discordgo.MessageEmbed {
+	var embeds []*discordgo.MessageEmbed
+	for i := 0; i < n; i++ {
+		embeds = append(embeds, &discordgo.MessageEmbed{Title: "Raid", Description: strings.Repeat("a", length)})
+	}
+	return embeds
+}
+
+func TestEmbedPagesCount(t *testing.T) {
+	pages := embedPages(testEmbeds(23, 10))
+	var sizes []int
+	for _, p := range pages {
+		sizes = append(sizes, len(p.embeds))
+	}
+	if len(sizes) != 3 || sizes[0] != maxEmbeds || sizes[1] != maxEmbeds || sizes[2] != 3 {
+		t.Errorf("23 embeds were split into pages of %v, want [10 10 3]", sizes)
+	}
+}
+
+func TestEmbedPagesLengthWithFooter(t *testing.T) {
+	// the longest footer addFreshness sets is the stale one
+	data := NewRefresher(nil, nil, time.Hour)
+	data.markSuccess(time.Now())
+	data.markFailed(errors.New("source is down"))
+
+	// three of these fill a page exactly, so only the room left for the footer keeps it under the limit
+	embeds := testEmbeds(7, maxEmbedsLength/3-len("Raid"))
+	// the footer addFreshness keeps in front of its own
+	embeds[len(embeds)-1].Footer = &discordgo.MessageEmbedFooter{Text: "CP with 15/15/15 IVs"}
+	pages := embedPages(embeds)
+	total := 0
+	for n, p := range pages {
+		addFreshness(data, p.embeds)
+		length := 0
+		for _, embed := range p.embeds {
+			length += embedLength(embed)
+		}
+		if length > maxEmbedsLength {
+			t.Errorf("page %d is %d characters with the footer, over the %d limit", n, length, maxEmbedsLength)
+		}
+		total += len(p.embeds)
+	}
+	if total != len(embeds) {
+		t.Errorf("pages hold %d embeds, want %d", total, len(embeds))
+	}
+}
@@ -0,0 +1,266 @@
+// refresh.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// folder holding a copy of the last data that was imported successfully
+const snapshotPath = "./snapshot"
+
+// struct describing how fresh the served data is
+type DataStatus struct {
+	LastSuccess time.Time // when the served data was imported, zero if unknown
+	Stale       bool      // true if the latest refresh failed
+	LastError   string    // error of the latest failed refresh
+}
+
+// struct to pull new data in the background, only one refresh runs at a time
+type Refresher struct {
+	store    Store // the database the data is imported into
+	src      DataSource
+	interval time.Duration
+	retries  int           // extra attempts when pulling the files fails
+	backoff  time.Duration // wait before the first retry, doubled after each attempt
+	trigger  chan struct{}
+	ctx      context.Context // cancelled by Stop, ends Run and any wait between retries
+	cancel   context.CancelFunc
+	mu       sync.Mutex // held while a refresh is running
+	pending  bool       // retry the import on the next run even if the files did not change
+	loaded   bool       // true once any data was imported by this process
+
+	// called after a refresh that changed the data, eg. to post the changes
+	OnChange func(diff *DatasetDiff)
+
+	statusMu sync.RWMutex
+	status   DataStatus
+}
+
+// func to create a refresher importing the given data source into the store
+func NewRefresher(st Store, src DataSource, interval time.Duration) *Refresher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Refresher{
+		store:    st,
+		src:      src,
+		interval: interval,
+		retries:  3,
+		backoff:  5 * time.Second,
+		trigger:  make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+		pending:  true,
+	}
+}
+
+// func to refresh right away, then on every tick or manual trigger until Stop is called
+func (r *Refresher) Run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := r.Refresh()
+		if err != nil && r.ctx.Err() == nil {
+			slog.Error("Error refreshing data", "err", err)
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+	}
+}
+
+// func to end Run and cut short a refresh that is waiting to retry
+func (r *Refresher) Stop() {
+	r.cancel()
+}
+
+// func to queue a refresh without waiting for it, returns false if one is already queued
+func (r *Refresher) Trigger() bool {
+	select {
+	case r.trigger <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// func to do a queued refresh right away, for when there is no Run loop, eg. in the terminal
+// returns false if no refresh was queued
+func (r *Refresher) RunQueued() (bool, error) {
+	select {
+	case <-r.trigger:
+		return r.Refresh()
+	default:
+		return false, nil
+	}
+}
+
+// func to pull the files and refresh the database
+// returns false without doing anything if another refresh is already running
+func (r *Refresher) Refresh() (bool, error) {
+	if !r.mu.TryLock() {
+		return false, nil
+	}
+	defer r.mu.Unlock()
+
+	err := r.refresh()
+	if err != nil && r.ctx.Err() != nil {
+		// stopped while waiting to retry, the bot is shutting down
+		return true, err
+	}
+	if err != nil {
+		r.markFailed(err)
+		// nothing imported yet, serve the last good snapshot instead
+		if !r.loaded {
+			r.loadSnapshot()
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// func doing the actual refresh, the caller holds the lock
+func (r *Refresher) refresh() error {
+	// pull only the files that changed since the last pull
+	changed, err := r.syncWithRetry()
+	if err != nil {
+		return fmt.Errorf("error pulling files from %v: %w", r.src, err)
+	}
+	if !changed && !r.pending {
+		slog.Debug("Files unchanged, skipping refresh", "source", r.src.String())
+		r.markSuccess(time.Now())
+		return nil
+	}
+
+	slog.Info("Files pulled successfully", "source", r.src.String())
+	// refresh the database with the new pulled data, the previous data is kept if this fails
+	diff, err := refreshDB(r.ctx, r.store, r.src)
+	if err != nil {
+		r.pending = true
+		return fmt.Errorf("error refreshing database, keeping previous data: %w", err)
+	}
+	r.pending = false
+	r.loaded = true
+	r.markSuccess(time.Now())
+
+	if diff != nil && !diff.Empty() && r.OnChange != nil {
+		r.OnChange(diff)
+	}
+
+	// keep a copy to fall back on if the source is down on a later start
+	err = saveSnapshot(r.src, snapshotPath)
+	if err != nil {
+		slog.Error("Error saving data snapshot", "err", err)
+	}
+	return nil
+}
+
+// func to sync the data source, retrying with a growing wait between attempts
+func (r *Refresher) syncWithRetry() (bool, error) {
+	wait := r.backoff
+	for attempt := 0; ; attempt++ {
+		changed, err := r.src.Sync()
+		if err == nil || attempt >= r.retries {
+			return changed, err
+		}
+		slog.Warn("Error pulling files, retrying", "source", r.src.String(), "wait", wait, "err", err)
+		select {
+		case <-time.After(wait):
+		case <-r.ctx.Done():
+			return false, fmt.Errorf("refresh stopped: %w", err)
+		}
+		wait *= 2
+	}
+}
+
+// func to import the saved snapshot when the data source is unavailable
+func (r *Refresher) loadSnapshot() {
+	info, err := os.Stat(filepath.Join(snapshotPath, "eggs.json"))
+	if err != nil {
+		slog.Warn("No data snapshot to fall back on", "err", err)
+		return
+	}
+
+	_, err = refreshDB(r.ctx, r.store, &DirSource{Path: snapshotPath})
+	if err != nil {
+		slog.Error("Error loading data snapshot", "err", err)
+		return
+	}
+	r.loaded = true
+	// the data is as old as the snapshot, not as the import
+	r.statusMu.Lock()
+	r.status.LastSuccess = info.ModTime()
+	r.statusMu.Unlock()
+	slog.Info("Loaded data snapshot", "from", info.ModTime().Format(time.RFC1123))
+}
+
+// func to take the time of the saved snapshot as the age of the data, for when no refresh runs
+// a snapshot is saved after every import, so it is as old as the data in the database
+func (r *Refresher) UseSnapshotTime() bool {
+	info, err := os.Stat(filepath.Join(snapshotPath, "eggs.json"))
+	if err != nil {
+		return false
+	}
+	r.markSuccess(info.ModTime())
+	return true
+}
+
+// func to record a successful refresh
+func (r *Refresher) markSuccess(at time.Time) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.status = DataStatus{LastSuccess: at}
+}
+
+// func to record a failed refresh, the last success time is kept
+func (r *Refresher) markFailed(err error) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.status.Stale = true
+	r.status.LastError = err.Error()
+}
+
+// func to get the current data status
+func (r *Refresher) Status() DataStatus {
+	r.statusMu.RLock()
+	defer r.statusMu.RUnlock()
+	return r.status
+}
+
+// func to copy the data files of a source into the snapshot folder
+func saveSnapshot(src DataSource, dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	for _, name := range dataFiles {
+		input, err := src.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		// write to a temp file first so a crash never leaves a half written snapshot
+		destPath := filepath.Join(dir, name)
+		err = os.WriteFile(destPath+".tmp", input, 0644)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(destPath+".tmp", destPath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
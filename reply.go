@@ -0,0 +1,144 @@
+// reply.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// struct for the reply to one command
+// the command is acknowledged right away and the result is edited in when it is ready
+type reply struct {
+	s         *discordgo.Session
+	i         *discordgo.InteractionCreate
+	ephemeral bool
+	data      *Refresher // for the freshness of the data shown in the reply
+
+	mu   sync.Mutex
+	sent bool // set once a result or an error is sent, anything sent after that is dropped
+}
+
+// func to acknowledge a command and run it, it fails with a timeout error if it does not finish in time
+func runCommand(s *discordgo.Session, i *discordgo.InteractionCreate, st Store, data *Refresher, timeout time.Duration) {
+	name := i.ApplicationCommandData().Name
+	cmd, ok := registry.Lookup(name)
+	if !ok {
+		// discord can still show commands that were removed until it reloads them
+		slog.Warn("Unknown command", "command", name)
+		return
+	}
+	r := &reply{s: s, i: i, ephemeral: isEphemeral(cmd), data: data}
+
+	// discord waits 3 seconds for an answer, until the result is in the user sees "thinking..."
+	var flags discordgo.MessageFlags
+	if r.ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: flags},
+	})
+	if err != nil {
+		slog.Error("Error acknowledging command", "command", name, "err", err)
+		return
+	}
+
+	// the queries of the command are cancelled with this, so a timed out command stops using the database
+	cmdCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ctx := discordContext(st, data, s, i, r)
+	ctx.Ctx = cmdCtx
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// a bug in one command should not take the whole bot down
+		defer func() {
+			if rec := recover(); rec != nil {
+				respondError(r, &InternalError{Op: "running command", Err: fmt.Errorf("panic: %v", rec)})
+			}
+		}()
+		err := cmd.Handle(ctx)
+		// a query failing because the time ran out is reported as the timeout below
+		if err != nil && cmdCtx.Err() == nil {
+			respondError(r, err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-cmdCtx.Done():
+	}
+	// the running query is cancelled, whatever the command still sends is dropped
+	if cmdCtx.Err() != nil {
+		respondError(r, &TimeoutError{Command: name, After: timeout})
+	}
+}
+
+// func to mark the reply as sent, false if something was sent already
+func (r *reply) claim() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sent {
+		return false
+	}
+	r.sent = true
+	return true
+}
+
+// func to fill in the acknowledged reply with the result
+func (r *reply) send(data *discordgo.InteractionResponseData) {
+	if !r.claim() {
+		slog.Warn("Dropping the result of a command that already failed", "command", interactionName(r.i))
+		return
+	}
+
+	// empty lists so the edit clears anything left over instead of sending null
+	embeds := append([]*discordgo.MessageEmbed{}, data.Embeds...)
+	components := append([]discordgo.MessageComponent{}, data.Components...)
+	_, err := r.s.InteractionResponseEdit(r.i.Interaction, &discordgo.WebhookEdit{
+		Content:    &data.Content,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		slog.Error("Error sending reply", "command", interactionName(r.i), "err", err)
+	}
+}
+
+// func to reply with an error only the user can see
+func (r *reply) fail(content string) {
+	if !r.claim() {
+		return
+	}
+
+	if r.ephemeral {
+		_, err := r.s.InteractionResponseEdit(r.i.Interaction, &discordgo.WebhookEdit{Content: &content})
+		if err != nil {
+			slog.Error("Error sending error reply", "command", interactionName(r.i), "err", err)
+		}
+		return
+	}
+
+	// a public "thinking..." reply can't be made private, so remove it and send the error as a follow up
+	err := r.s.InteractionResponseDelete(r.i.Interaction)
+	if err != nil {
+		slog.Error("Error removing reply", "command", interactionName(r.i), "err", err)
+	}
+	_, err = r.s.FollowupMessageCreate(r.i.Interaction, true, &discordgo.WebhookParams{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		slog.Error("Error sending error reply", "command", interactionName(r.i), "err", err)
+	}
+}
@@ -0,0 +1,232 @@
+// resolver.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// words naming a regional form or variant, mapped to how the form is written in names
+var formWords = map[string]string{
+	"alola":    "alolan",
+	"alolan":   "alolan",
+	"galar":    "galarian",
+	"galarian": "galarian",
+	"hisui":    "hisuian",
+	"hisuian":  "hisuian",
+	"paldea":   "paldean",
+	"paldean":  "paldean",
+	"mega":     "mega",
+	"shadow":   "shadow",
+}
+
+// letters replaced before matching so "flabebe" finds "Flabébé" and "nidoran female" finds "Nidoran♀"
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c",
+	"♀", " female", "♂", " male",
+)
+
+// nicknames players commonly use, added to the aliases from the config
+var defaultAliases = map[string]string{
+	"ttar":  "Tyranitar",
+	"zard":  "Charizard",
+	"dnite": "Dragonite",
+	"chomp": "Garchomp",
+	"gyara": "Gyarados",
+	"meta":  "Metagross",
+	"rhyp":  "Rhyperior",
+	"mamo":  "Mamoswine",
+}
+
+// resolver used by every command, replaced on start with one holding the configured aliases
+var resolver = NewNameResolver(nil)
+
+// struct to match what users type to pokemon names, forgiving case, accents, punctuation, form order and typos
+type NameResolver struct {
+	aliases map[string]string // name key of the alias to the name it stands for
+}
+
+// func to create a resolver, aliases map a nickname to a pokemon name and override the defaults
+func NewNameResolver(aliases map[string]string) *NameResolver {
+	r := &NameResolver{aliases: map[string]string{}}
+	for alias, name := range defaultAliases {
+		r.aliases[nameKey(alias)] = name
+	}
+	for alias, name := range aliases {
+		r.aliases[nameKey(alias)] = name
+	}
+	return r
+}
+
+// func to find the name the input refers to
+// returns the name and true on a match, or the closest name and false, empty if nothing is close
+func (r *NameResolver) Resolve(input string, names []string) (string, bool) {
+	key := r.key(input)
+	keys := make(map[string]string, len(names))
+	for _, name := range names {
+		keys[nameKey(name)] = name
+	}
+
+	if name, ok := keys[key]; ok {
+		return name, true
+	}
+	// shadow pokemon share the stats of the normal one, so fall back to it
+	if strings.HasPrefix(key, "shadow ") {
+		if name, ok := keys[strings.TrimPrefix(key, "shadow ")]; ok {
+			return name, true
+		}
+	}
+
+	matches := r.rank(key, names, 1, true)
+	if len(matches) == 0 {
+		return "", false
+	}
+	return matches[0], false
+}
+
+// func to get up to limit names for the input, best matches first
+// names containing the input come before names that only match with typos
+func (r *NameResolver) Match(input string, names []string, limit int) []string {
+	return r.rank(r.key(input), names, limit, false)
+}
+
+// func to get the name key of the input, following an alias if there is one
+func (r *NameResolver) key(input string) string {
+	key := nameKey(input)
+	if name, ok := r.aliases[key]; ok {
+		return nameKey(name)
+	}
+	return key
+}
+
+// func to rank names against a key, typosOnly skips names that contain the key
+func (r *NameResolver) rank(key string, names []string, limit int, typosOnly bool) []string {
+	type scored struct {
+		name  string
+		score int
+	}
+
+	// allow about one typo for every three letters
+	maxDistance := len([]rune(key))/3 + 1
+	var matches []scored
+	for _, name := range names {
+		nk := nameKey(name)
+		switch {
+		case typosOnly:
+		case strings.HasPrefix(nk, key) || strings.HasPrefix(baseOf(nk), key):
+			matches = append(matches, scored{name, -2})
+			continue
+		case strings.Contains(nk, key):
+			matches = append(matches, scored{name, -1})
+			continue
+		}
+		distance := editDistance(key, nk)
+		if distance <= maxDistance {
+			matches = append(matches, scored{name, distance})
+		}
+	}
+
+	// stable so names with the same score keep the order they were given in
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+	var result []string
+	for _, m := range matches {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, m.name)
+	}
+	return result
+}
+
+// func to get the words of a name without accents, punctuation or case
+func normalizeName(name string) []string {
+	name = accentReplacer.Replace(strings.ToLower(name))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		case r == '\'' || r == '’' || r == '.':
+			// farfetch'd and mr. mime are written without them too
+			return -1
+		}
+		return ' '
+	}, name)
+	return strings.Fields(name)
+}
+
+// func to build the key names are compared by, eg. "Meowth (Galar)" and "galarian meowth" both give "galarian meowth"
+// form words come first, the rest of the name is joined without spaces so "ho oh" matches "Ho-Oh"
+func nameKey(name string) string {
+	var forms []string
+	var base []string
+	for _, word := range normalizeName(name) {
+		if form, ok := formWords[word]; ok {
+			forms = append(forms, form)
+		} else {
+			base = append(base, word)
+		}
+	}
+	// shadow first so it can be stripped as a prefix
+	sort.Slice(forms, func(i, j int) bool {
+		if (forms[i] == "shadow") != (forms[j] == "shadow") {
+			return forms[i] == "shadow"
+		}
+		return forms[i] < forms[j]
+	})
+	return strings.TrimSpace(strings.Join(forms, " ") + " " + strings.Join(base, ""))
+}
+
+// func to check if a name is the pokemon the input names or one of its forms
+// eg. "ponyta" matches "Ponyta" and "Galarian Ponyta", "galar ponyta" only the galarian one, "mew" never matches "Mewtwo"
+func isPokemonOrForm(name string, input string) bool {
+	key := nameKey(input)
+	if key == "" {
+		return false
+	}
+	nk := nameKey(name)
+	if nk == key {
+		return true
+	}
+	// the base name has no spaces, so an input without form words is a key without spaces
+	return !strings.Contains(key, " ") && baseOf(nk) == key
+}
+
+// func to get the part of a name key after the form words
+func baseOf(key string) string {
+	return key[strings.LastIndex(key, " ")+1:]
+}
+
+// func to count the single letter edits needed to turn a into b
+func editDistance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
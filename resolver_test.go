@@ -0,0 +1,115 @@
+// resolver_test.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// names as the stats table has them
+var testNames = []string{
+	"Mew", "Mewtwo", "Meowth", "Galarian Meowth", "Alolan Meowth", "Ponyta", "Galarian Ponyta",
+	"Flabébé", "Gardevoir", "Mega Gardevoir", "Nidoran♀", "Nidoran♂", "Ho-Oh", "Farfetch'd", "Mr. Mime", "Tyranitar",
+}
+
+func TestResolve(t *testing.T) {
+	r := NewNameResolver(map[string]string{"pinky": "Mew"})
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"mewtwo", "Mewtwo", true},
+		{"Mewtwo ", "Mewtwo", true},
+		{"MEWTWO", "Mewtwo", true},
+		{"mew", "Mew", true},
+		{"flabebe", "Flabébé", true},
+		{"Flabébé", "Flabébé", true},
+		{"galar meowth", "Galarian Meowth", true},
+		{"Galarian Meowth", "Galarian Meowth", true},
+		{"meowth galar", "Galarian Meowth", true},
+		{"Meowth (Alola)", "Alolan Meowth", true},
+		{"meowth", "Meowth", true},
+		{"mega gardevoir", "Mega Gardevoir", true},
+		{"gardevoir mega", "Mega Gardevoir", true},
+		{"gardevoir", "Gardevoir", true},
+		// shadow pokemon fall back to the normal one
+		{"shadow mewtwo", "Mewtwo", true},
+		{"nidoran female", "Nidoran♀", true},
+		{"ho oh", "Ho-Oh", true},
+		{"farfetchd", "Farfetch'd", true},
+		{"mr mime", "Mr. Mime", true},
+		// default and configured aliases
+		{"ttar", "Tyranitar", true},
+		{"pinky", "Mew", true},
+		// typos give a suggestion that is not taken as the answer
+		{"mewto", "Mewtwo", false},
+		{"gardevior", "Gardevoir", false},
+		{"zzzzzzzz", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := r.Resolve(tt.input, testNames)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Resolve(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	r := NewNameResolver(nil)
+	tests := []struct {
+		input string
+		limit int
+		want  []string
+	}{
+		// names starting with the input first, in the order given, then typos
+		{"meow", 10, []string{"Meowth", "Galarian Meowth", "Alolan Meowth", "Mew"}},
+		{"meow", 2, []string{"Meowth", "Galarian Meowth"}},
+		{"mewtwo", 1, []string{"Mewtwo"}},
+		{"flab", 5, []string{"Flabébé"}},
+		{"galar", 5, []string{"Galarian Meowth", "Galarian Ponyta"}},
+		{"ttar", 5, []string{"Tyranitar"}},
+		{"zzzzzzzz", 5, nil},
+	}
+	for _, tt := range tests {
+		got := r.Match(tt.input, testNames, tt.limit)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Match(%q, %d) = %q, want %q", tt.input, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestIsPokemonOrForm(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"Mew", "mew", true},
+		{"Mewtwo", "mew", false},
+		{"Mew", "mewtwo", false},
+		{"Mewtwo", "Mewtwo ", true},
+		{"Ponyta", "ponyta", true},
+		{"Galarian Ponyta", "ponyta", true},
+		{"Ponyta", "galar ponyta", false},
+		{"Galarian Ponyta", "galar ponyta", true},
+		{"Alolan Meowth", "galarian meowth", false},
+		{"Flabébé", "flabebe", true},
+		{"Mega Gardevoir", "gardevoir", true},
+		{"Gardevoir", "mega gardevoir", false},
+		{"Shadow Mewtwo", "mewtwo", true},
+		{"Nidoran♀", "Nidoran♀", true},
+		{"Nidoran♂", "Nidoran♀", false},
+		{"Mewtwo", "", false},
+	}
+	for _, tt := range tests {
+		got := isPokemonOrForm(tt.name, tt.input)
+		if got != tt.want {
+			t.Errorf("isPokemonOrForm(%q, %q) = %v, want %v", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
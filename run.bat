@@ -1,2 +1,2 @@
-go run main.go getFiles.go
+go run .
 exit
\ No newline at end of file
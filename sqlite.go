@@ -0,0 +1,189 @@
+// sqlite.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// pure go driver, no c compiler is needed to build the bot
+	_ "modernc.org/sqlite"
+)
+
+// csv files the static tables are filled from when they are empty, the first row is a header
+var sqliteSeeds = []struct {
+	table   string
+	columns string
+	path    string
+}{
+	{"pokemon_data", "name, hp, attack, defense", "./pogodb/pokemon_data.csv"},
+	{"newdps2", "name, fmove, ftype, cmove, ctype, dps, tdo, er, cp", "./pogodb/newdps2.csv"},
+}
+
+// func to open an sqlite database file, creating or upgrading the schema
+func newSQLiteStore(path string) (*sqlStore, error) {
+	// wal lets commands read while a refresh is writing, busy_timeout makes writers wait instead of failing
+	// times are written as "2006-01-02 15:04:05-07:00" so they compare in order like the mysql datetimes
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_time_format=sqlite")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(4)
+
+	// ping the database to verify the file can be opened
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	err = migrate(db, "sqlite")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for _, seed := range sqliteSeeds {
+		err = seedTable(db, seed.table, seed.columns, seed.path)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("error seeding %s: %w", seed.table, err)
+		}
+	}
+	return &sqlStore{db: db, dialect: "sqlite"}, nil
+}
+
+// func to fill an empty table from a csv file, skipped if the table has rows
+// a missing file leaves the table empty, /hundo, /best and autocomplete report the data unavailable until it is added
+func seedTable(db *sql.DB, table string, columns string, path string) error {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// the table is still empty on the next start, so the file is loaded once it is there
+		slog.Warn("Seed file is missing, create it from a mysql pogodb with \"go run . export-seeds\"", "table", table, "path", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	// skip the header row
+	_, err = reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	// no-op once the transaction is committed
+	defer tx.Rollback()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", strings.Count(columns, ",")+1), ", ")
+	query := "INSERT INTO " + table + " (" + columns + ") VALUES (" + placeholders + ")"
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		args := make([]any, len(record))
+		for i, value := range record {
+			args[i] = value
+		}
+		_, err = tx.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// func to write the static tables of the database to the csv files sqlite is seeded from
+func exportSeeds(st Store) error {
+	sqlSt, ok := st.(*sqlStore)
+	if !ok {
+		return errors.New("the store is not an sql database")
+	}
+	for _, seed := range sqliteSeeds {
+		err := exportTable(sqlSt.db, seed.table, seed.columns, seed.path)
+		if err != nil {
+			return fmt.Errorf("error exporting %s: %w", seed.table, err)
+		}
+		slog.Info("Exported seed file", "table", seed.table, "path", seed.path)
+	}
+	return nil
+}
+
+// func to write every row of a table to a csv file with a header row
+func exportTable(db *sql.DB, table string, columns string, path string) error {
+	rows, err := db.Query("SELECT " + columns + " FROM " + table + " ORDER BY name")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	header := strings.Split(columns, ", ")
+	var records [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(header))
+		dest := make([]any, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			return err
+		}
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = value.String
+		}
+		records = append(records, record)
+	}
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("%s has no rows to export", table)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write(header)
+	writer.WriteAll(records)
+	return writer.Error()
+}
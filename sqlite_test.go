@@ -0,0 +1,160 @@
+// sqlite_test.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// func to open an sqlite store in a temp file, the static tables are left empty like a checkout without seed files
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+	dir := t.TempDir()
+	saved := sqliteSeeds
+	sqliteSeeds = nil
+	for _, seed := range saved {
+		seed.path = filepath.Join(dir, filepath.Base(seed.path))
+		sqliteSeeds = append(sqliteSeeds, seed)
+	}
+	t.Cleanup(func() { sqliteSeeds = saved })
+
+	st, err := newSQLiteStore(filepath.Join(dir, "test.sqlite"))
+	if err != nil {
+		t.Fatalf("opening sqlite store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+// func to read the dataset from the json files in data
+func loadTestDataset(t *testing.T) *Dataset {
+	t.Helper()
+	ds, err := loadDataset(&DirSource{Path: "data"})
+	if err != nil {
+		t.Fatalf("loading data: %v", err)
+	}
+	return ds
+}
+
+func TestSQLiteStoreServesReplacedDataset(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStore(t)
+	ds := loadTestDataset(t)
+	err := st.ReplaceDataset(ctx, ds, nil)
+	if err != nil {
+		t.Fatalf("ReplaceDataset: %v", err)
+	}
+
+	raids, err := st.Raids(ctx, "all")
+	if err != nil {
+		t.Fatalf("Raids: %v", err)
+	}
+	if len(raids) != len(ds.Raids) {
+		t.Errorf("Raids(all) returned %d raids, want %d", len(raids), len(ds.Raids))
+	}
+
+	eggsByDistance := map[string]int{}
+	for _, e := range ds.Eggs {
+		eggsByDistance[e.EggType]++
+	}
+	for distance, want := range eggsByDistance {
+		eggs, err := st.Eggs(ctx, distance)
+		if err != nil {
+			t.Fatalf("Eggs(%q): %v", distance, err)
+		}
+		if len(eggs) != want {
+			t.Errorf("Eggs(%q) returned %d eggs, want %d", distance, len(eggs), want)
+		}
+	}
+
+	rewards := 0
+	for _, task := range ds.Researches {
+		rewards += len(task.Rewards)
+	}
+	research, err := st.Research(ctx, "", "")
+	if err != nil {
+		t.Fatalf("Research: %v", err)
+	}
+	if len(research) != rewards {
+		t.Errorf("Research returned %d rewards, want %d", len(research), rewards)
+	}
+
+	boss := ds.Raids[0].Name
+	sources, err := st.Where(ctx, boss, time.Now())
+	if err != nil {
+		t.Fatalf("Where(%q): %v", boss, err)
+	}
+	found := false
+	for _, src := range sources {
+		found = found || (src.Kind == "raid" && src.Name == boss)
+	}
+	if !found {
+		t.Errorf("Where(%q) = %+v, want its raid", boss, sources)
+	}
+
+	// events are stored as wall clock times, so they are live at their local start time
+	event := ds.Events[0]
+	start, err := time.ParseInLocation("2006-01-02T15:04:05.000", event.Start, time.Local)
+	if err != nil {
+		t.Fatalf("parsing start of %q: %v", event.Name, err)
+	}
+	events, err := st.Events(ctx, "live", "", start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Events: %v", err)
+	}
+	found = false
+	for _, e := range events {
+		found = found || e.EventID == event.EventID
+	}
+	if !found {
+		t.Errorf("Events(live) at the start of %q did not return it", event.Name)
+	}
+}
+
+func TestSQLiteStoreSavesChanges(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStore(t)
+	ds := loadTestDataset(t)
+	err := st.ReplaceDataset(ctx, ds, nil)
+	if err != nil {
+		t.Fatalf("ReplaceDataset: %v", err)
+	}
+
+	// drop the first raid boss from the next dataset
+	next := *ds
+	next.Raids = ds.Raids[1:]
+	err = st.ReplaceDataset(ctx, &next, diffDatasets(ds, &next))
+	if err != nil {
+		t.Fatalf("ReplaceDataset with diff: %v", err)
+	}
+
+	diff, err := st.LatestChanges(ctx)
+	if err != nil {
+		t.Fatalf("LatestChanges: %v", err)
+	}
+	if diff == nil || len(diff.RemovedRaids) != 1 || diff.RemovedRaids[0].Name != ds.Raids[0].Name {
+		t.Errorf("LatestChanges = %+v, want %q removed from raids", diff, ds.Raids[0].Name)
+	}
+}
+
+func TestSQLiteStoreWithoutSeeds(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStore(t)
+
+	var unavailable *DataUnavailableError
+	_, err := resolvePokemon(ctx, st, "mewtwo")
+	if !errors.As(err, &unavailable) {
+		t.Errorf("resolvePokemon without stats = %v, want a data unavailable error", err)
+	}
+	_, err = getBest(ctx, st, "sametype", "dps", "3", "fire")
+	if !errors.As(err, &unavailable) {
+		t.Errorf("getBest without attackers = %v, want a data unavailable error", err)
+	}
+}
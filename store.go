@@ -0,0 +1,544 @@
+// store.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// struct for one row of the best attackers table
+type Attacker struct {
+	Name        string
+	FastMove    string
+	FastType    string
+	ChargedMove string
+	ChargedType string
+	DPS         string
+	TDO         string
+	ER          string
+	CP          string
+}
+
+// struct for the base stats of a pokemon
+type PokemonStats struct {
+	Name    string
+	HP      int
+	Attack  int
+	Defense int
+}
+
+// struct for one place a pokemon can currently be found
+type PokemonSource struct {
+	Kind   string // "egg", "raid", "research" or "event"
+	Name   string
+	Detail string // egg distance, raid tier, research task or event name
+	Role   string // how the pokemon is featured in an event, or "Adventure Sync" for eggs
+	Shiny  bool
+	MinCP  int
+	MaxCP  int
+	Start  string // start time, events only
+}
+
+// interface every command and the data refresh go through to reach the database
+type Store interface {
+	Eggs(ctx context.Context, distance string) ([]Egg, error)
+	Raids(ctx context.Context, tier string) ([]Raid, error)
+	// returns nil if there is no pokemon with the name
+	Hundo(ctx context.Context, name string) (*PokemonStats, error)
+	// names of every pokemon with base stats
+	PokemonNames(ctx context.Context) ([]string, error)
+	// url of a sprite of the pokemon from the current data, empty if there is none
+	PokemonImage(ctx context.Context, name string) (string, error)
+	BestAttackers(ctx context.Context, search string, sort string, num string, nameOrType string) ([]Attacker, error)
+	// false until the best attackers table is filled
+	HasAttackers(ctx context.Context) (bool, error)
+	// when is "live" or "week", event times are compared to now
+	Events(ctx context.Context, when string, eventType string, now time.Time) ([]Event, error)
+	Research(ctx context.Context, reward string, taskType string) ([]ResearchReward, error)
+	Where(ctx context.Context, pokemon string, now time.Time) ([]PokemonSource, error)
+
+	// swap in a new dataset and save its diff in one step, diff can be nil
+	ReplaceDataset(ctx context.Context, ds *Dataset, diff *DatasetDiff) error
+	// returns nil if no changes were saved yet
+	LatestChanges(ctx context.Context) (*DatasetDiff, error)
+
+	SetAnnounceChannel(ctx context.Context, guildID string, channelID string) error
+	RemoveAnnounceChannel(ctx context.Context, guildID string) error
+	AnnounceChannels(ctx context.Context) ([]string, error)
+
+	Close() error
+}
+
+// store backed by a sql database, shares one connection pool between all callers
+type sqlStore struct {
+	db      *sql.DB
+	dialect string // "mysql" or "sqlite"
+}
+
+// func to open the store for the given database type
+// dsn is a mysql connection string or the path of the sqlite file
+func newStore(dbType string, dsn string) (Store, error) {
+	switch dbType {
+	case "mysql", "":
+		return newMySQLStore(dsn)
+	case "sqlite":
+		return newSQLiteStore(dsn)
+	}
+	return nil, fmt.Errorf("unknown database type %q", dbType)
+}
+
+// func to connect to the mysql database and create the tables the bot manages
+func newMySQLStore(dsn string) (*sqlStore, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep a few connections ready, commands are short and bursty
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	// ping the database to verify the connection
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	err = migrate(db, "mysql")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqlStore{db: db, dialect: "mysql"}, nil
+}
+
+func (st *sqlStore) Close() error {
+	return st.db.Close()
+}
+
+// func to get the pokemon hatching from eggs of a distance
+func (st *sqlStore) Eggs(ctx context.Context, distance string) ([]Egg, error) {
+	query := "SELECT name, distance, adventure_sync, image, shiny, min_cp, max_cp, regional FROM eggs WHERE distance = ?;"
+
+	rows, err := st.db.QueryContext(ctx, query, distance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var eggs []Egg
+	for rows.Next() {
+		var e Egg
+		err = rows.Scan(&e.Name, &e.EggType, &e.IsAdventureSync, &e.Image, &e.CanBeShiny, &e.CombatPower.Min, &e.CombatPower.Max, &e.IsRegional)
+		if err != nil {
+			return nil, err
+		}
+		eggs = append(eggs, e)
+	}
+	return eggs, rows.Err()
+}
+
+// func to get the raid bosses of a tier, "all" gets every tier
+func (st *sqlStore) Raids(ctx context.Context, tier string) ([]Raid, error) {
+	columns := "name, tier, shiny, types, min_cp, max_cp, wb_min_cp, wb_max_cp, boosted_weather, image"
+	query := "SELECT " + columns + " FROM raids WHERE tier = ?;"
+	args := []any{tier}
+	if tier == "all" {
+		query = "SELECT " + columns + " FROM raids;"
+		args = nil
+	}
+
+	rows, err := st.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var raids []Raid
+	for rows.Next() {
+		var r Raid
+		var types string
+		var boostedWeather string
+		err = rows.Scan(&r.Name, &r.Tier, &r.CanBeShiny, &types, &r.CombatPower.Normal.Min, &r.CombatPower.Normal.Max,
+			&r.CombatPower.Boosted.Min, &r.CombatPower.Boosted.Max, &boostedWeather, &r.Image)
+		if err != nil {
+			return nil, err
+		}
+		r.Types = splitTypes(types)
+		r.BoostedWeather = splitTypes(boostedWeather)
+		raids = append(raids, r)
+	}
+	return raids, rows.Err()
+}
+
+// func to get the base stats of a pokemon
+func (st *sqlStore) Hundo(ctx context.Context, name string) (*PokemonStats, error) {
+	query := "SELECT name, hp, attack, defense FROM pokemon_data WHERE name = ?;"
+
+	var p PokemonStats
+	err := st.db.QueryRowContext(ctx, query, name).Scan(&p.Name, &p.HP, &p.Attack, &p.Defense)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// every pokemon type, the best command searches moves by these
+var pokemonTypes = []string{
+	"Normal", "Fire", "Water", "Grass", "Electric", "Ice", "Fighting", "Poison", "Ground",
+	"Flying", "Psychic", "Bug", "Rock", "Ghost", "Dragon", "Dark", "Steel", "Fairy",
+}
+
+// sort_by choices of the best command and the newdps2 column each one orders by
+var attackerSortColumns = map[string]string{
+	"dps": "dps",
+	"tdo": "tdo",
+	"er":  "er",
+}
+
+// number_of_results choices of the best command
+var attackerResultLimits = map[string]int{
+	"1":  1,
+	"3":  3,
+	"5":  5,
+	"10": 10,
+}
+
+// func to get the names of every pokemon in the base stats table
+func (st *sqlStore) PokemonNames(ctx context.Context) ([]string, error) {
+	rows, err := st.db.QueryContext(ctx, "SELECT name FROM pokemon_data WHERE name IS NOT NULL ORDER BY name;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// func to get a sprite of a pokemon from the eggs, raids, research or events it is in
+func (st *sqlStore) PokemonImage(ctx context.Context, name string) (string, error) {
+	var image string
+	err := st.db.QueryRowContext(ctx, `SELECT image FROM (
+			SELECT image FROM raids WHERE name = ?
+			UNION ALL SELECT image FROM eggs WHERE name = ?
+			UNION ALL SELECT image FROM researches WHERE reward = ?
+			UNION ALL SELECT image FROM event_pokemon WHERE name = ?
+		) images WHERE image <> '' LIMIT 1;`, name, name, name, name).Scan(&image)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return image, err
+}
+
+// func to get the best attackers by name, same type moves or mixed type moves
+func (st *sqlStore) BestAttackers(ctx context.Context, search string, sort string, num string, nameOrType string) ([]Attacker, error) {
+	// column names can't be placeholders, so the sort and limit only come from the lists above
+	column, ok := attackerSortColumns[sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort column %q", sort)
+	}
+	limit, ok := attackerResultLimits[num]
+	if !ok {
+		return nil, fmt.Errorf("unknown number of results %q", num)
+	}
+
+	var where string
+	var args []any
+	switch search {
+	case "name":
+		where = "name = ?"
+		args = []any{nameOrType}
+	case "sametype":
+		where = "ftype = ? AND ctype = ?"
+		args = []any{nameOrType, nameOrType}
+	case "mixtype":
+		where = "ctype = ?"
+		args = []any{nameOrType}
+	default:
+		return nil, fmt.Errorf("unknown search setting %q", search)
+	}
+
+	// build the sql query for all collected data
+	query := "SELECT name, fmove, ftype, cmove, ctype, dps, tdo, er, cp FROM newdps2 WHERE " + where + " ORDER BY " + column + " DESC LIMIT ?;"
+	args = append(args, limit)
+
+	rows, err := st.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attackers []Attacker
+	for rows.Next() {
+		var a Attacker
+		err = rows.Scan(&a.Name, &a.FastMove, &a.FastType, &a.ChargedMove, &a.ChargedType, &a.DPS, &a.TDO, &a.ER, &a.CP)
+		if err != nil {
+			return nil, err
+		}
+		attackers = append(attackers, a)
+	}
+	return attackers, rows.Err()
+}
+
+// func to check if the best attackers table has any rows, an sqlite database is empty until it is seeded
+func (st *sqlStore) HasAttackers(ctx context.Context) (bool, error) {
+	var found bool
+	err := st.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM newdps2);").Scan(&found)
+	return found, err
+}
+
+// func to get the live events or the events starting in the next 7 days
+func (st *sqlStore) Events(ctx context.Context, when string, eventType string, now time.Time) ([]Event, error) {
+	// event times are stored as local wall clock times
+	now = wallClock(now)
+
+	query := "SELECT event_id, name, event_type, heading, link, image, start_time, end_time FROM events WHERE start_time <= ? AND end_time >= ?"
+	args := []any{now, now}
+	if when == "week" {
+		query = "SELECT event_id, name, event_type, heading, link, image, start_time, end_time FROM events WHERE start_time > ? AND start_time <= ?"
+		args = []any{now, now.AddDate(0, 0, 7)}
+	}
+	if eventType != "" {
+		query = query + " AND event_type = ?"
+		args = append(args, eventType)
+	}
+	query = query + " ORDER BY start_time;"
+
+	rows, err := st.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var start sql.NullString
+		var end sql.NullString
+		err = rows.Scan(&e.EventID, &e.Name, &e.EventType, &e.Heading, &e.Link, &e.Image, &start, &end)
+		if err != nil {
+			return nil, err
+		}
+		e.Start = start.String
+		e.End = end.String
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// func to get the research tasks matching a reward and/or task type
+func (st *sqlStore) Research(ctx context.Context, reward string, taskType string) ([]ResearchReward, error) {
+	// the reward is matched by name key after the query like /where does, so "mew" does not find "Mewtwo"
+	query := "SELECT text, type, reward, shiny, min_cp, max_cp, image FROM researches WHERE 1 = 1"
+	var args []any
+	if taskType != "" {
+		query = query + " AND type = ?"
+		args = append(args, taskType)
+	}
+	query = query + " ORDER BY type, text;"
+
+	rows, err := st.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var research []ResearchReward
+	for rows.Next() {
+		var r ResearchReward
+		err = rows.Scan(&r.Task, &r.Type, &r.Reward.Name, &r.Reward.CanBeShiny, &r.Reward.CombatPower.Min, &r.Reward.CombatPower.Max, &r.Reward.Image)
+		if err != nil {
+			return nil, err
+		}
+		if reward == "" || isPokemonOrForm(r.Reward.Name, reward) {
+			research = append(research, r)
+		}
+	}
+	return research, rows.Err()
+}
+
+// func to get every egg, raid, research task and live or upcoming event featuring a pokemon
+func (st *sqlStore) Where(ctx context.Context, pokemon string, now time.Time) ([]PokemonSource, error) {
+	// the names are matched by name key, which sql can't do, the tables only hold the current rotation
+	// forms match too, eg. "ponyta" also finds "Galarian Ponyta" but "mew" does not find "Mewtwo"
+	queries := []struct {
+		kind  string
+		query string
+		args  []any
+	}{
+		{"egg", "SELECT name, distance, CASE WHEN adventure_sync THEN 'Adventure Sync' ELSE '' END, shiny, min_cp, max_cp, '' FROM eggs ORDER BY name;", nil},
+		{"raid", "SELECT name, tier, '', shiny, min_cp, max_cp, '' FROM raids ORDER BY name;", nil},
+		{"research", "SELECT reward, text, '', shiny, min_cp, max_cp, '' FROM researches ORDER BY reward;", nil},
+		// the shiny rows only mark event shinies so they are skipped
+		{"event", `SELECT p.name, e.name, p.role, p.shiny, 0, 0, e.start_time FROM event_pokemon p
+			JOIN events e ON e.event_id = p.event_id
+			WHERE p.role <> ? AND e.end_time >= ? ORDER BY e.start_time;`, []any{roleShiny, wallClock(now)}},
+	}
+
+	var sources []PokemonSource
+	for _, q := range queries {
+		rows, err := st.db.QueryContext(ctx, q.query, q.args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			src := PokemonSource{Kind: q.kind}
+			var start sql.NullString
+			err = rows.Scan(&src.Name, &src.Detail, &src.Role, &src.Shiny, &src.MinCP, &src.MaxCP, &start)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			src.Start = start.String
+			if isPokemonOrForm(src.Name, pokemon) {
+				sources = append(sources, src)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// func to replace the eggs, events, raids and research in one transaction
+// readers keep seeing the old data until the commit
+func (st *sqlStore) ReplaceDataset(ctx context.Context, ds *Dataset, diff *DatasetDiff) error {
+	tx, err := st.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// no-op once the transaction is committed
+	defer tx.Rollback()
+
+	err = clearTables(tx)
+	if err != nil {
+		return err
+	}
+	err = insertEggs(tx, ds.Eggs)
+	if err != nil {
+		return err
+	}
+	err = insertEvents(tx, ds.Events)
+	if err != nil {
+		return err
+	}
+	err = insertRaids(tx, ds.Raids)
+	if err != nil {
+		return err
+	}
+	err = insertResearches(tx, ds.Researches)
+	if err != nil {
+		return err
+	}
+	if diff != nil && !diff.Empty() {
+		err = insertDiff(tx, diff)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// func to get the last saved dataset changes
+func (st *sqlStore) LatestChanges(ctx context.Context) (*DatasetDiff, error) {
+	var changes string
+	err := st.db.QueryRowContext(ctx, "SELECT changes FROM dataset_changes ORDER BY id DESC LIMIT 1").Scan(&changes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &DatasetDiff{}
+	err = json.Unmarshal([]byte(changes), diff)
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// func to set the channel changes are posted to for a guild
+func (st *sqlStore) SetAnnounceChannel(ctx context.Context, guildID string, channelID string) error {
+	// replace in a transaction, mysql and sqlite have no shared upsert syntax
+	tx, err := st.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// no-op once the transaction is committed
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM announce_channels WHERE guild_id = ?", guildID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO announce_channels (guild_id, channel_id) VALUES (?, ?)", guildID, channelID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// func to stop posting changes for a guild
+func (st *sqlStore) RemoveAnnounceChannel(ctx context.Context, guildID string) error {
+	_, err := st.db.ExecContext(ctx, "DELETE FROM announce_channels WHERE guild_id = ?", guildID)
+	return err
+}
+
+// func to get every channel changes are posted to
+func (st *sqlStore) AnnounceChannels(ctx context.Context) ([]string, error) {
+	rows, err := st.db.QueryContext(ctx, "SELECT channel_id FROM announce_channels")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []string
+	for rows.Next() {
+		var channelID string
+		err = rows.Scan(&channelID)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channelID)
+	}
+	return channels, rows.Err()
+}
+
+// func to split a comma separated list of types or weathers
+func splitTypes(input string) []TypeInfo {
+	var types []TypeInfo
+	for _, name := range strings.Split(input, ",") {
+		if name != "" {
+			types = append(types, TypeInfo{Name: name})
+		}
+	}
+	return types
+}
@@ -0,0 +1,254 @@
+// terminal.go
+// Author: Cade Beckers
+// Written: 10/18/2026
+// Updated: 10/18/2026
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// struct printing the reply of a command to the terminal
+type printer struct {
+	w    io.Writer
+	data *Refresher // for the freshness of the data shown in the reply
+}
+
+// func to print plain text
+func (p *printer) Text(msg string) {
+	fmt.Fprintln(p.w, strings.TrimRight(msg, "\n"))
+}
+
+// func to print the best attackers ranking
+func (p *printer) Best(result *BestResult) {
+	fmt.Fprintln(p.w, "== "+result.Title()+" ==")
+	if len(result.Attackers) == 0 {
+		if result.Search == "name" {
+			fmt.Fprintln(p.w, "No attacker data for "+result.Name+".")
+		} else {
+			fmt.Fprintln(p.w, "No "+result.Name+" attackers found.")
+		}
+	}
+	for count, a := range result.Attackers {
+		fmt.Fprintln(p.w, "  #"+strconv.Itoa(count+1)+" "+a.Name+": "+a.FastMove+" ("+titleCase(a.FastType)+") / "+a.ChargedMove+" ("+titleCase(a.ChargedType)+")")
+		fmt.Fprintln(p.w, "    DPS: "+a.DPS+"  |  TDO: "+a.TDO+"  |  Rating: "+a.ER+"  |  CP: "+a.CP)
+	}
+	p.freshness()
+}
+
+// func to print the live or upcoming events
+func (p *printer) Events(when string, events []Event) {
+	if when == "week" {
+		fmt.Fprintln(p.w, "== Events in the next 7 days ==")
+	} else {
+		fmt.Fprintln(p.w, "== Live events ==")
+	}
+	for _, e := range events {
+		fmt.Fprintln(p.w, e.Name+" ("+e.Heading+")")
+		fmt.Fprintln(p.w, "  Starts: "+terminalTime(e.Start)+"  |  Ends: "+terminalTime(e.End))
+		fmt.Fprintln(p.w, "  "+e.Link)
+	}
+	if len(events) == 0 {
+		fmt.Fprintln(p.w, "No events found.")
+	}
+	p.freshness()
+}
+
+// func to print the hundo cp of a pokemon
+func (p *printer) Hundo(result *HundoResult) {
+	stats := result.Pokemon
+	fmt.Fprintln(p.w, "== "+stats.Name+" ==")
+	fmt.Fprintln(p.w, "Base stats: "+strconv.Itoa(stats.Attack)+" Atk / "+strconv.Itoa(stats.Defense)+" Def / "+strconv.Itoa(stats.HP)+" HP")
+	for _, level := range result.Levels {
+		fmt.Fprintln(p.w, "  "+level.Label+": "+strconv.Itoa(level.CP))
+	}
+	fmt.Fprintln(p.w, "CP with 15/15/15 IVs")
+	p.freshness()
+}
+
+// func to print the pokemon hatching from eggs
+func (p *printer) Eggs(eggs []Egg) {
+	fmt.Fprintln(p.w, "== Eggs ==")
+	for _, e := range eggs {
+		line := "  " + shinyMark(e.CanBeShiny) + e.Name + " - " + eggLabel(e) + "   CP: " + cpRange(e.CombatPower.Min, e.CombatPower.Max) +
+			"   Shiny: " + convertBool(e.CanBeShiny)
+		if e.IsRegional {
+			line = line + "   Regional"
+		}
+		fmt.Fprintln(p.w, line)
+	}
+	if len(eggs) == 0 {
+		fmt.Fprintln(p.w, "No eggs found.")
+	}
+	p.freshness()
+}
+
+// func to print the raid bosses
+func (p *printer) Raids(raids []Raid) {
+	fmt.Fprintln(p.w, "== Raids ==")
+	for _, r := range raids {
+		var types []string
+		for _, t := range r.Types {
+			types = append(types, titleCase(t.Name))
+		}
+		var weather []string
+		for _, w := range r.BoostedWeather {
+			weather = append(weather, titleCase(w.Name))
+		}
+
+		fmt.Fprintln(p.w, shinyMark(r.CanBeShiny)+r.Name+" ("+r.Tier+")")
+		fmt.Fprintln(p.w, "  Types: "+orNone(strings.Join(types, " / "))+"   Shiny: "+convertBool(r.CanBeShiny))
+		fmt.Fprintln(p.w, "  CP: "+cpRange(r.CombatPower.Normal.Min, r.CombatPower.Normal.Max)+"   Weather boosted CP: "+
+			cpRange(r.CombatPower.Boosted.Min, r.CombatPower.Boosted.Max)+"   Boosted in: "+orNone(strings.Join(weather, ", ")))
+	}
+	if len(raids) == 0 {
+		fmt.Fprintln(p.w, "No raids found.")
+	}
+	p.freshness()
+}
+
+// func to print research tasks with their rewards
+func (p *printer) Research(research []ResearchReward) {
+	fmt.Fprintln(p.w, "== Research ==")
+	last_task := ""
+	for _, r := range research {
+		if r.Task != last_task {
+			fmt.Fprintln(p.w, r.Task+" ("+r.Type+")")
+			last_task = r.Task
+		}
+		fmt.Fprintln(p.w, "  "+r.Reward.Name+"   cp: "+strconv.Itoa(r.Reward.CombatPower.Min)+"-"+strconv.Itoa(r.Reward.CombatPower.Max)+
+			"   Shiny: "+convertBool(r.Reward.CanBeShiny))
+	}
+	if len(research) == 0 {
+		fmt.Fprintln(p.w, "No research tasks found.")
+	}
+	p.freshness()
+}
+
+// func to print the current sources of a pokemon
+func (p *printer) Where(pokemon string, sources []PokemonSource) {
+	headers := map[string]string{"egg": "Eggs:", "raid": "Raids:", "research": "Research:", "event": "Events:"}
+	fmt.Fprintln(p.w, "== Where to find "+pokemon+" ==")
+	last_kind := ""
+
+	for _, src := range sources {
+		if src.Kind != last_kind {
+			fmt.Fprintln(p.w, headers[src.Kind])
+			last_kind = src.Kind
+		}
+		cp := "   cp: " + strconv.Itoa(src.MinCP) + "-" + strconv.Itoa(src.MaxCP)
+
+		line := "  " + src.Name + " - "
+		switch src.Kind {
+		case "egg":
+			egg := src.Detail
+			if src.Role != "" {
+				egg = egg + " " + src.Role
+			}
+			line = line + egg + " egg" + cp
+		case "raid":
+			line = line + src.Detail + " raid" + cp
+		case "research":
+			line = line + src.Detail + cp
+		case "event":
+			line = line + eventRoleLabel(src.Role) + ": " + src.Detail + "   Starts: " + terminalTime(src.Start)
+		}
+		fmt.Fprintln(p.w, line+"   Shiny: "+convertBool(src.Shiny))
+	}
+	if len(sources) == 0 {
+		fmt.Fprintln(p.w, "No current sources found.")
+	}
+	p.freshness()
+}
+
+// func to print the raid, egg and research changes
+func (p *printer) Changes(diff *DatasetDiff) {
+	fmt.Fprintln(p.w, "== Raid, egg and research rotation changed ("+diff.Time.Local().Format("Mon Jan 2 2006 15:04")+") ==")
+	list := func(name string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintln(p.w, name+":")
+		for _, line := range lines {
+			fmt.Fprintln(p.w, "  "+line)
+		}
+	}
+
+	var added, removed []string
+	for _, r := range diff.AddedRaids {
+		added = append(added, shinyMark(r.CanBeShiny)+r.Name+" ("+r.Tier+")")
+	}
+	for _, r := range diff.RemovedRaids {
+		removed = append(removed, r.Name+" ("+r.Tier+")")
+	}
+	list("New raid bosses", added)
+	list("Left raids", removed)
+
+	added, removed = nil, nil
+	for _, e := range diff.AddedEggs {
+		added = append(added, shinyMark(e.CanBeShiny)+e.Name+" ("+eggLabel(e)+")")
+	}
+	for _, e := range diff.RemovedEggs {
+		removed = append(removed, e.Name+" ("+eggLabel(e)+")")
+	}
+	list("New in eggs", added)
+	list("Left eggs", removed)
+
+	added, removed = nil, nil
+	for _, r := range diff.AddedResearch {
+		added = append(added, shinyMark(r.Reward.CanBeShiny)+r.Reward.Name+" - "+r.Task)
+	}
+	for _, r := range diff.RemovedResearch {
+		removed = append(removed, r.Reward.Name+" - "+r.Task)
+	}
+	list("New research rewards", added)
+	list("Removed research rewards", removed)
+
+	var debuts []string
+	for _, debut := range diff.NewShinies {
+		debuts = append(debuts, "✨ "+debut.Name+" - "+debut.Source)
+	}
+	list("Shiny debuts", debuts)
+}
+
+// func to print the progress towards an xp goal
+func (p *printer) Xp(progress XpProgress) {
+	fmt.Fprintln(p.w, "Xp to "+progress.Level+": "+strconv.Itoa(int(progress.Remaining))+"  |  Percent of xp gained: "+fmt.Sprintf("%.2f", progress.Percent)+"%")
+}
+
+// func to print when the data was refreshed, the same the discord footer says
+func (p *printer) freshness() {
+	if p.data == nil {
+		return
+	}
+	status := p.data.Status()
+	if status.LastSuccess.IsZero() {
+		fmt.Fprintln(p.w, unknownFooter)
+		return
+	}
+	text := freshFooter
+	if status.Stale {
+		text = staleFooter
+	}
+	fmt.Fprintln(p.w, text+" "+status.LastSuccess.Local().Format("Mon Jan 2 2006 15:04"))
+}
+
+// func to format a stored event time for the terminal, eg. "Wed Sep 11 2024 18:00 (in 2h0m0s)"
+func terminalTime(value string) string {
+	t, err := parseStoredTime(value)
+	if err != nil {
+		return "unknown"
+	}
+	// relative, eg. "in 2h30m0s" or "45m0s ago"
+	d := time.Until(t).Round(time.Minute)
+	relative := "in " + d.String()
+	if d < 0 {
+		relative = (-d).String() + " ago"
+	}
+	return t.Format("Mon Jan 2 2006 15:04") + " (" + relative + ")"
+}